@@ -0,0 +1,53 @@
+package geohash
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// binarySize is the number of bytes of the binary encoding of a geohash
+const binarySize = 8
+
+// ErrInvalidBinaryLength is returned when decoding a binary geohash that is not 8 bytes long
+var ErrInvalidBinaryLength = errors.New("geohash: binary geohash must be 8 bytes")
+
+// MarshalBinary encodes the geohash as 8 bytes, the big endian of Hash.Int64(),
+// so the byte order of the encodings of the same precision is the order of the geohash strings.
+// Returns ErrInvalidPrecision if the bit precision is zero or greater than MaxPackedBitPrecision
+func (h Hash) MarshalBinary() ([]byte, error) {
+	return h.AppendBinary(make([]byte, 0, binarySize))
+}
+
+// AppendBinary appends the 8 bytes of MarshalBinary to b, it does not allocate if b has enough capacity
+func (h Hash) AppendBinary(b []byte) ([]byte, error) {
+	value, err := h.Int64()
+	if err != nil {
+		return b, err
+	}
+
+	var data [binarySize]byte
+	binary.BigEndian.PutUint64(data[:], uint64(value))
+	return append(b, data[:]...), nil
+}
+
+// UnmarshalBinary decodes the 8 bytes of MarshalBinary.
+// Returns ErrInvalidBinaryLength if the data is not 8 bytes long and
+// ErrInvalidPrecision if the data does not contain a valid marker bit or contains only the marker bit
+func (h *Hash) UnmarshalBinary(data []byte) error {
+	if len(data) != binarySize {
+		return ErrInvalidBinaryLength
+	}
+
+	value := int64(binary.BigEndian.Uint64(data))
+	if value <= 0 {
+		// no marker bit or the marker bit at the 64th bit
+		return ErrInvalidPrecision
+	}
+
+	result, err := FromInt64(value)
+	if err != nil {
+		return err
+	}
+	*h = result
+	return nil
+}
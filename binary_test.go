@@ -0,0 +1,65 @@
+package geohash
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestHash_MarshalBinary(t *testing.T) {
+	data, err := mustParse("s").MarshalBinary()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []byte{0, 0, 0, 0, 0, 0, 0, 0b111000}, data)
+
+	_, err = Hash{}.MarshalBinary()
+	assert.Equal(t, ErrInvalidPrecision, err)
+
+	_, err = ComputeGeohashBits(Pos{}, 63).MarshalBinary()
+	assert.Equal(t, ErrInvalidPrecision, err)
+
+	for _, bitCount := range []uint32{1, 3, 5, 30, 60, 62} {
+		h := ComputeGeohashBits(Pos{Lat: 10.7769, Lon: 106.7009}, bitCount)
+
+		data, err := h.MarshalBinary()
+		assert.Equal(t, nil, err)
+		assert.Equal(t, 8, len(data))
+
+		var result Hash
+		assert.Equal(t, nil, result.UnmarshalBinary(data))
+		assert.Equal(t, h, result)
+	}
+}
+
+func TestHash_AppendBinary(t *testing.T) {
+	buf := make([]byte, 0, 32)
+	buf = append(buf, 'x')
+
+	buf, err := mustParse("s").AppendBinary(buf)
+	assert.Equal(t, nil, err)
+	buf, err = mustParse("u").AppendBinary(buf)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []byte{'x', 0, 0, 0, 0, 0, 0, 0, 0b111000, 0, 0, 0, 0, 0, 0, 0, 0b111010}, buf)
+
+	buf, err = ComputeGeohashBits(Pos{}, 63).AppendBinary(buf)
+	assert.Equal(t, ErrInvalidPrecision, err)
+	assert.Equal(t, 17, len(buf))
+
+	h := mustParse("w3gv2")
+	buf = make([]byte, 0, 8)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf, _ = h.AppendBinary(buf[:0])
+	})
+	assert.Equal(t, 0.0, allocs)
+}
+
+func TestHash_UnmarshalBinary_Errors(t *testing.T) {
+	h := mustParse("s")
+
+	assert.Equal(t, ErrInvalidBinaryLength, h.UnmarshalBinary(nil))
+	assert.Equal(t, ErrInvalidBinaryLength, h.UnmarshalBinary(make([]byte, 9)))
+	assert.Equal(t, ErrInvalidPrecision, h.UnmarshalBinary(make([]byte, 8)))
+	assert.Equal(t, ErrInvalidPrecision, h.UnmarshalBinary([]byte{0, 0, 0, 0, 0, 0, 0, 1}))
+
+	// the marker bit of 63 bits is greater than MaxPackedBitPrecision
+	assert.Equal(t, ErrInvalidPrecision, h.UnmarshalBinary([]byte{0x80, 0, 0, 0, 0, 0, 0, 1}))
+	assert.Equal(t, mustParse("s"), h)
+}
@@ -0,0 +1,357 @@
+package geohash
+
+import (
+	"hash/fnv"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	concurrentIndexShards  = 64
+	concurrentIndexStripes = 64
+)
+
+// maxShardPrecision keeps the prefixes of the shards representable by Hash.Uint64
+const maxShardPrecision = 12
+
+// ConcurrentIndex is a point index that is safe for concurrent use.
+// Points are partitioned into shards by their geohash prefixes at the shard precision,
+// each shard is an Index protected by its own read write lock.
+// Operations on the same id are serialized by striped locks, and operations locking
+// multiple shards always lock them in ascending order, so moving a point between shards
+// is atomic for the queries
+type ConcurrentIndex struct {
+	// number of occupied cells of all shards, accessed atomically
+	cells int64
+
+	precision      uint32
+	shardPrecision uint32
+
+	shards  [concurrentIndexShards]indexShard
+	stripes [concurrentIndexStripes]idStripe
+}
+
+type indexShard struct {
+	mut   sync.RWMutex
+	index *Index
+}
+
+// idStripe maps the ids to their current shards
+type idStripe struct {
+	mut    sync.Mutex
+	shards map[string]int
+}
+
+// NewConcurrentIndex creates an empty concurrent index storing points at the precision (in characters),
+// sharded by the geohash prefixes at the shard precision.
+// Returns ErrInvalidPrecision if the precision is zero or greater than MaxPrecision,
+// or the shard precision is zero, greater than the precision or greater than 12
+func NewConcurrentIndex(precision uint32, shardPrecision uint32) (*ConcurrentIndex, error) {
+	if precision == 0 || precision > MaxPrecision {
+		return nil, ErrInvalidPrecision
+	}
+	if shardPrecision == 0 || shardPrecision > precision || shardPrecision > maxShardPrecision {
+		return nil, ErrInvalidPrecision
+	}
+
+	c := &ConcurrentIndex{
+		precision:      precision,
+		shardPrecision: shardPrecision,
+	}
+	for i := range c.shards {
+		c.shards[i].index, _ = NewIndex(precision)
+	}
+	for i := range c.stripes {
+		c.stripes[i].shards = map[string]int{}
+	}
+	return c, nil
+}
+
+// Precision returns the storage precision of the index
+func (c *ConcurrentIndex) Precision() uint32 {
+	return c.precision
+}
+
+// Len returns the number of points
+func (c *ConcurrentIndex) Len() int {
+	shards := c.allShards()
+	c.readLockShards(shards)
+	defer c.readUnlockShards(shards)
+
+	count := 0
+	for _, shard := range shards {
+		count += c.shards[shard].index.Len()
+	}
+	return count
+}
+
+// Insert adds a new point, returns ErrDuplicatedID if the id already exists
+func (c *ConcurrentIndex) Insert(id string, pos Pos, value interface{}) error {
+	if err := validatePos(pos); err != nil {
+		return err
+	}
+
+	stripe := c.stripeOf(id)
+	stripe.mut.Lock()
+	defer stripe.mut.Unlock()
+
+	if _, existed := stripe.shards[id]; existed {
+		return ErrDuplicatedID
+	}
+
+	shardIndex := c.shardOfPos(pos)
+	shard := &c.shards[shardIndex]
+
+	shard.mut.Lock()
+	before := shard.index.cellCount()
+	err := shard.index.Insert(id, pos, value)
+	c.addCells(shard.index, before)
+	shard.mut.Unlock()
+
+	if err != nil {
+		return err
+	}
+	stripe.shards[id] = shardIndex
+	return nil
+}
+
+// Update changes the position and the value of an existing point.
+// If the point moves to another shard, both shards are locked during the move.
+// Returns ErrPointNotFound if the id does not exist
+func (c *ConcurrentIndex) Update(id string, pos Pos, value interface{}) error {
+	if err := validatePos(pos); err != nil {
+		return err
+	}
+
+	stripe := c.stripeOf(id)
+	stripe.mut.Lock()
+	defer stripe.mut.Unlock()
+
+	oldShard, existed := stripe.shards[id]
+	if !existed {
+		return ErrPointNotFound
+	}
+
+	newShard := c.shardOfPos(pos)
+	if newShard == oldShard {
+		shard := &c.shards[newShard]
+		shard.mut.Lock()
+		defer shard.mut.Unlock()
+
+		before := shard.index.cellCount()
+		defer c.addCells(shard.index, before)
+
+		return shard.index.Update(id, pos, value)
+	}
+
+	shards := []int{oldShard, newShard}
+	sort.Ints(shards)
+	c.lockShards(shards)
+	defer c.unlockShards(shards)
+
+	oldIndex := c.shards[oldShard].index
+	newIndex := c.shards[newShard].index
+
+	before := oldIndex.cellCount()
+	oldIndex.Delete(id)
+	c.addCells(oldIndex, before)
+
+	before = newIndex.cellCount()
+	err := newIndex.Insert(id, pos, value)
+	c.addCells(newIndex, before)
+
+	if err != nil {
+		return err
+	}
+	stripe.shards[id] = newShard
+	return nil
+}
+
+// Delete removes a point, returns false if the id does not exist
+func (c *ConcurrentIndex) Delete(id string) bool {
+	stripe := c.stripeOf(id)
+	stripe.mut.Lock()
+	defer stripe.mut.Unlock()
+
+	shardIndex, existed := stripe.shards[id]
+	if !existed {
+		return false
+	}
+	delete(stripe.shards, id)
+
+	shard := &c.shards[shardIndex]
+	shard.mut.Lock()
+	defer shard.mut.Unlock()
+
+	before := shard.index.cellCount()
+	defer c.addCells(shard.index, before)
+
+	return shard.index.Delete(id)
+}
+
+// Get returns the point with the id
+func (c *ConcurrentIndex) Get(id string) (Point, bool) {
+	stripe := c.stripeOf(id)
+	stripe.mut.Lock()
+	defer stripe.mut.Unlock()
+
+	shardIndex, existed := stripe.shards[id]
+	if !existed {
+		return Point{}, false
+	}
+
+	shard := &c.shards[shardIndex]
+	shard.mut.RLock()
+	defer shard.mut.RUnlock()
+
+	return shard.index.Get(id)
+}
+
+// SearchRadius is the same as Index.SearchRadius, only the shards near the origin are read locked.
+// All shards are read locked if the occupied cells are scanned
+func (c *ConcurrentIndex) SearchRadius(origin Pos, radius float64) []PointDistance {
+	if radius < 0 {
+		return nil
+	}
+
+	if radiusCoveringSize(origin, radius, c.precision) > c.occupiedCells() {
+		shards := c.allShards()
+		c.readLockShards(shards)
+		defer c.readUnlockShards(shards)
+
+		return radiusPointsByScanning(c, c.precision, origin, radius)
+	}
+
+	hashes := NearbyGeohashList(origin, radius, c.precision)
+
+	shards := c.shardsOfHashes(hashes)
+	c.readLockShards(shards)
+	defer c.readUnlockShards(shards)
+
+	return radiusPoints(c, hashes, origin, radius)
+}
+
+// SearchBounds is the same as Index.SearchBounds, only the shards intersecting the bounds are read locked.
+// All shards are read locked if the occupied cells are scanned
+func (c *ConcurrentIndex) SearchBounds(bounds Bounds) []Point {
+	if boundsCoveringSize(bounds, c.precision) > c.occupiedCells() {
+		shards := c.allShards()
+		c.readLockShards(shards)
+		defer c.readUnlockShards(shards)
+
+		return boundsPointsByScanning(c, c.precision, bounds)
+	}
+
+	hashes := CoverRectangle(bounds, c.precision)
+
+	shards := c.shardsOfHashes(hashes)
+	c.readLockShards(shards)
+	defer c.readUnlockShards(shards)
+
+	return boundsPoints(c, hashes, bounds)
+}
+
+// Nearest is the same as Index.Nearest, all shards are read locked during the search
+func (c *ConcurrentIndex) Nearest(origin Pos, k int) []PointDistance {
+	shards := c.allShards()
+	c.readLockShards(shards)
+	defer c.readUnlockShards(shards)
+
+	return nearest(c, c.precision, origin, k)
+}
+
+// cellCount, forEachCell and forEachPointInCell must be called with the shards locked
+func (c *ConcurrentIndex) cellCount() int {
+	count := 0
+	for i := range c.shards {
+		count += c.shards[i].index.cellCount()
+	}
+	return count
+}
+
+func (c *ConcurrentIndex) forEachCell(fn func(h Hash)) {
+	for i := range c.shards {
+		c.shards[i].index.forEachCell(fn)
+	}
+}
+
+func (c *ConcurrentIndex) forEachPointInCell(h Hash, fn func(p Point)) {
+	c.shards[c.shardOfHash(h)].index.forEachPointInCell(h, fn)
+}
+
+// occupiedCells returns the number of occupied cells without locking the shards,
+// it is only used to choose between listing the covering cells and scanning the occupied cells
+func (c *ConcurrentIndex) occupiedCells() uint64 {
+	return uint64(atomic.LoadInt64(&c.cells))
+}
+
+// addCells must be called with the shard of the index locked, before is its cell count before the change
+func (c *ConcurrentIndex) addCells(index *Index, before int) {
+	atomic.AddInt64(&c.cells, int64(index.cellCount()-before))
+}
+
+func (c *ConcurrentIndex) stripeOf(id string) *idStripe {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(id))
+	return &c.stripes[hash.Sum32()%concurrentIndexStripes]
+}
+
+func (c *ConcurrentIndex) shardOfPos(pos Pos) int {
+	return c.shardOfHash(ComputeGeohash(pos, c.shardPrecision))
+}
+
+// shardOfHash returns the shard of the geohash prefix, h must not be coarser than the shard precision
+func (c *ConcurrentIndex) shardOfHash(h Hash) int {
+	// the shard precision is not greater than maxShardPrecision
+	value, _ := h.truncate(c.shardPrecision * 5).Uint64()
+	return int(value % concurrentIndexShards)
+}
+
+// shardsOfHashes returns the sorted distinct shards of the geohashes
+func (c *ConcurrentIndex) shardsOfHashes(hashes []Hash) []int {
+	var found [concurrentIndexShards]bool
+	for _, h := range hashes {
+		found[c.shardOfHash(h)] = true
+	}
+
+	var result []int
+	for shard, ok := range found {
+		if ok {
+			result = append(result, shard)
+		}
+	}
+	return result
+}
+
+func (c *ConcurrentIndex) allShards() []int {
+	result := make([]int, 0, concurrentIndexShards)
+	for shard := range c.shards {
+		result = append(result, shard)
+	}
+	return result
+}
+
+func (c *ConcurrentIndex) lockShards(shards []int) {
+	for _, shard := range shards {
+		c.shards[shard].mut.Lock()
+	}
+}
+
+func (c *ConcurrentIndex) unlockShards(shards []int) {
+	for _, shard := range shards {
+		c.shards[shard].mut.Unlock()
+	}
+}
+
+func (c *ConcurrentIndex) readLockShards(shards []int) {
+	for _, shard := range shards {
+		c.shards[shard].mut.RLock()
+	}
+}
+
+func (c *ConcurrentIndex) readUnlockShards(shards []int) {
+	for _, shard := range shards {
+		c.shards[shard].mut.RUnlock()
+	}
+}
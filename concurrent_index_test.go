@@ -0,0 +1,148 @@
+package geohash
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestConcurrentIndex(t *testing.T, precision uint32, shardPrecision uint32) *ConcurrentIndex {
+	c, err := NewConcurrentIndex(precision, shardPrecision)
+	assert.Equal(t, nil, err)
+	return c
+}
+
+func TestNewConcurrentIndex(t *testing.T) {
+	_, err := NewConcurrentIndex(0, 1)
+	assert.Equal(t, ErrInvalidPrecision, err)
+
+	_, err = NewConcurrentIndex(5, 0)
+	assert.Equal(t, ErrInvalidPrecision, err)
+
+	_, err = NewConcurrentIndex(5, 6)
+	assert.Equal(t, ErrInvalidPrecision, err)
+
+	_, err = NewConcurrentIndex(14, 13)
+	assert.Equal(t, ErrInvalidPrecision, err)
+
+	c := newTestConcurrentIndex(t, 6, 2)
+	assert.Equal(t, uint32(6), c.Precision())
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestConcurrentIndex_Insert_Update_Delete(t *testing.T) {
+	c := newTestConcurrentIndex(t, 6, 2)
+
+	hcm := Pos{Lat: 10.7769, Lon: 106.7009}
+	hanoi := Pos{Lat: 21.0285, Lon: 105.8542}
+	assert.NotEqual(t, c.shardOfPos(hcm), c.shardOfPos(hanoi))
+
+	assert.Equal(t, nil, c.Insert("a", hcm, 1))
+	assert.Equal(t, ErrDuplicatedID, c.Insert("a", hanoi, 2))
+	assert.Equal(t, ErrInvalidLatitude, c.Insert("b", Pos{Lat: -91}, 2))
+	assert.Equal(t, ErrPointNotFound, c.Update("b", hanoi, 2))
+	assert.Equal(t, 1, c.Len())
+
+	// in the same shard
+	assert.Equal(t, nil, c.Update("a", Pos{Lat: 10.8, Lon: 106.7}, 3))
+	p, ok := c.Get("a")
+	assert.Equal(t, true, ok)
+	assert.Equal(t, Point{ID: "a", Pos: Pos{Lat: 10.8, Lon: 106.7}, Value: 3}, p)
+
+	// moving to another shard
+	assert.Equal(t, nil, c.Update("a", hanoi, 4))
+	p, ok = c.Get("a")
+	assert.Equal(t, true, ok)
+	assert.Equal(t, Point{ID: "a", Pos: hanoi, Value: 4}, p)
+	assert.Equal(t, 1, c.Len())
+	assert.Equal(t, 0, c.shards[c.shardOfPos(hcm)].index.Len())
+
+	assert.Equal(t, []string{"a"}, nearestIDs(c.SearchRadius(hanoi, 1)))
+	assert.Equal(t, []PointDistance(nil), c.SearchRadius(hcm, 1))
+
+	assert.Equal(t, true, c.Delete("a"))
+	assert.Equal(t, false, c.Delete("a"))
+	_, ok = c.Get("a")
+	assert.Equal(t, false, ok)
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestConcurrentIndex_Same_As_Index(t *testing.T) {
+	seed := time.Now().Unix()
+	fmt.Println("SEED:", seed)
+	rand.Seed(seed)
+
+	idx := newTestIndex(t, 5)
+	c := newTestConcurrentIndex(t, 5, 2)
+
+	for i := 0; i < 3000; i++ {
+		id := strconv.Itoa(i)
+		pos := Pos{Lat: mathRand(0, 20), Lon: mathRand(100, 120)}
+		assert.Equal(t, nil, idx.Insert(id, pos, i))
+		assert.Equal(t, nil, c.Insert(id, pos, i))
+	}
+
+	for i := 0; i < 20; i++ {
+		origin := Pos{Lat: mathRand(0, 20), Lon: mathRand(100, 120)}
+
+		radius := mathRand(0, 200)
+		assert.Equal(t, idx.SearchRadius(origin, radius), c.SearchRadius(origin, radius))
+
+		k := randInt(1, 30)
+		assert.Equal(t, idx.Nearest(origin, k), c.Nearest(origin, k))
+
+		bounds := Bounds{
+			Min: origin,
+			Max: Pos{Lat: origin.Lat + mathRand(0, 3), Lon: origin.Lon + mathRand(0, 3)},
+		}
+		assert.Equal(t, idx.SearchBounds(bounds), c.SearchBounds(bounds))
+	}
+}
+
+func TestConcurrentIndex_Concurrent_Moves(t *testing.T) {
+	c := newTestConcurrentIndex(t, 3, 1)
+
+	const numPoints = 200
+	for i := 0; i < numPoints; i++ {
+		assert.Equal(t, nil, c.Insert(strconv.Itoa(i), Pos{Lat: 1, Lon: 1}, nil))
+	}
+
+	area := Bounds{
+		Min: Pos{Lat: -10, Lon: -10},
+		Max: Pos{Lat: 10, Lon: 10},
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+
+			r := rand.New(rand.NewSource(int64(w)))
+			for i := 0; i < 500; i++ {
+				id := strconv.Itoa(r.Intn(numPoints))
+				pos := Pos{Lat: r.Float64()*20 - 10, Lon: r.Float64()*20 - 10}
+				assert.Equal(t, nil, c.Update(id, pos, nil))
+			}
+		}(w)
+	}
+
+	for q := 0; q < 2; q++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < 50; i++ {
+				// the moves are atomic, no point is seen twice or missed
+				assert.Equal(t, numPoints, len(c.SearchBounds(area)))
+				assert.Equal(t, numPoints, len(c.Nearest(Pos{}, numPoints+10)))
+				assert.Equal(t, numPoints, c.Len())
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,145 @@
+package geohash
+
+import "math"
+
+// Bounds is a lat/lon box, Min is the bottom left corner and Max is the top right corner.
+// Min.Lon > Max.Lon means the box crosses the antimeridian
+type Bounds struct {
+	Min Pos
+	Max Pos
+}
+
+// Contains checks whether the position is inside the bounds, including the edges.
+// The bounds are normalized the same as CoverRectangle
+func (b Bounds) Contains(pos Pos) bool {
+	b, ok := b.normalize()
+	if !ok {
+		return false
+	}
+
+	if pos.Lat < b.Min.Lat || pos.Lat > b.Max.Lat {
+		return false
+	}
+
+	if b.Min.Lon <= b.Max.Lon {
+		return pos.Lon >= b.Min.Lon && pos.Lon <= b.Max.Lon
+	}
+	// crossing the antimeridian
+	return pos.Lon >= b.Min.Lon || pos.Lon <= b.Max.Lon
+}
+
+// CoverRectangle computes all geohashes covering the bounds, ordered by rows from the bottom.
+// The latitudes are clamped into [-90, 90] and the longitudes outside of [-180, 180] are wrapped,
+// bounds spanning at least 360 degrees of longitude cover the whole longitude range.
+// Returns nil if Min.Lat > Max.Lat, the bounds do not overlap [-90, 90], a coordinate is NaN or infinite
+// or the precision is zero or greater than MaxPrecision
+func CoverRectangle(bounds Bounds, precision uint32) []Hash {
+	start, latSteps, lonSteps, ok := rectangleSteps(bounds, precision)
+	if !ok {
+		return nil
+	}
+
+	result := make([]Hash, 0, (latSteps+1)*(lonSteps+1))
+	for lat := 0; lat <= latSteps; lat++ {
+		for lon := 0; lon <= lonSteps; lon++ {
+			result = append(result, start.addOffset(posOffset{
+				lat: lat,
+				lon: lon,
+			}))
+		}
+	}
+	return result
+}
+
+// CoverRectangleMaxCells is the same as CoverRectangle but lowers the precision
+// until the number of geohashes is not greater than maxCells (maxCells <= 0 means no limit).
+// The cells at precision 1 are returned if no precision satisfies maxCells
+func CoverRectangleMaxCells(bounds Bounds, precision uint32, maxCells int) []Hash {
+	if maxCells <= 0 {
+		return CoverRectangle(bounds, precision)
+	}
+
+	for ; precision > 1; precision-- {
+		_, latSteps, lonSteps, ok := rectangleSteps(bounds, precision)
+		if !ok {
+			return nil
+		}
+
+		rows := uint64(latSteps) + 1
+		columns := uint64(lonSteps) + 1
+		if rows <= uint64(maxCells)/columns {
+			break
+		}
+	}
+	return CoverRectangle(bounds, precision)
+}
+
+// rectangleSteps returns the bottom left geohash and the number of steps to the top right geohash
+func rectangleSteps(bounds Bounds, precision uint32) (start Hash, latSteps int, lonSteps int, ok bool) {
+	if precision == 0 || precision > MaxPrecision {
+		return Hash{}, 0, 0, false
+	}
+
+	bounds, ok = bounds.normalize()
+	if !ok {
+		return Hash{}, 0, 0, false
+	}
+
+	start = ComputeGeohash(bounds.Min, precision)
+	end := ComputeGeohash(bounds.Max, precision)
+
+	_, lonPrecision := bitPrecisions(start.bits)
+	lonCount := int(1) << lonPrecision
+
+	latSteps = int(end.lat) - int(start.lat)
+	lonSteps = int(end.lon) - int(start.lon)
+
+	if bounds.Min.Lon > bounds.Max.Lon {
+		lonSteps += lonCount
+	}
+	if lonSteps >= lonCount {
+		// the bounds wrap around the whole longitude range
+		lonSteps = lonCount - 1
+	}
+
+	return start, latSteps, lonSteps, true
+}
+
+// normalize clamps the latitudes into [-90, 90] and wraps the longitudes into [-180, 180],
+// the bounds spanning at least 360 degrees of longitude become the whole longitude range
+func (b Bounds) normalize() (Bounds, bool) {
+	for _, value := range []float64{b.Min.Lat, b.Min.Lon, b.Max.Lat, b.Max.Lon} {
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return Bounds{}, false
+		}
+	}
+	if b.Min.Lat > b.Max.Lat || b.Min.Lat > 90 || b.Max.Lat < -90 {
+		return Bounds{}, false
+	}
+
+	b.Min.Lat = math.Max(b.Min.Lat, -90)
+	b.Max.Lat = math.Min(b.Max.Lat, 90)
+
+	if b.Max.Lon-b.Min.Lon >= 360 {
+		b.Min.Lon = -180
+		b.Max.Lon = 180
+		return b, true
+	}
+
+	b.Min.Lon = normalizeLon(b.Min.Lon)
+	b.Max.Lon = normalizeLon(b.Max.Lon)
+	return b, true
+}
+
+// normalizeLon wraps the longitude outside of [-180, 180] into [-180, 180)
+func normalizeLon(lon float64) float64 {
+	if lon >= -180 && lon <= 180 {
+		return lon
+	}
+
+	lon = math.Mod(lon+180, 360)
+	if lon < 0 {
+		lon += 360
+	}
+	return lon - 180
+}
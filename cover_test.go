@@ -0,0 +1,231 @@
+package geohash
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestCoverRectangle(t *testing.T) {
+	const size = 1.40625
+
+	hashes := CoverRectangle(Bounds{
+		Min: Pos{Lat: 0.5, Lon: 0.5},
+		Max: Pos{Lat: size + 0.5, Lon: size + 0.5},
+	}, 3)
+	assert.Equal(t, []Hash{
+		mustParse("s00"), mustParse("s01"),
+		mustParse("s02"), mustParse("s03"),
+	}, hashes)
+
+	hashes = CoverRectangle(Bounds{
+		Min: Pos{Lat: 0.5, Lon: 0.5},
+		Max: Pos{Lat: 0.6, Lon: 0.6},
+	}, 3)
+	assert.Equal(t, []Hash{mustParse("s00")}, hashes)
+
+	hashes = CoverRectangle(Bounds{
+		Min: Pos{Lat: 0.5, Lon: 0.5},
+		Max: Pos{Lat: 0.4, Lon: 0.6},
+	}, 3)
+	assert.Equal(t, []Hash(nil), hashes)
+}
+
+func TestCoverRectangle_Antimeridian(t *testing.T) {
+	hashes := CoverRectangle(Bounds{
+		Min: Pos{Lat: 10, Lon: 179},
+		Max: Pos{Lat: 11, Lon: -179},
+	}, 2)
+	assert.Equal(t, []Hash{
+		mustParse("xc"), mustParse("81"),
+	}, hashes)
+
+	// the whole longitude range except a small gap
+	hashes = CoverRectangle(Bounds{
+		Min: Pos{Lat: 10, Lon: 10.5},
+		Max: Pos{Lat: 11, Lon: 10.4},
+	}, 1)
+	assert.Equal(t, []Hash{
+		mustParse("s"), mustParse("t"), mustParse("w"), mustParse("x"),
+		mustParse("8"), mustParse("9"), mustParse("d"), mustParse("e"),
+	}, hashes)
+}
+
+func TestCoverRectangle_Whole_World(t *testing.T) {
+	hashes := CoverRectangle(Bounds{
+		Min: Pos{Lat: -90, Lon: -180},
+		Max: Pos{Lat: 90, Lon: 180},
+	}, 2)
+	assert.Equal(t, 32*32, len(hashes))
+
+	set := hashListToStrings(hashes)
+	assert.Equal(t, 32*32, len(set))
+}
+
+func TestCoverRectangle_Out_Of_Range(t *testing.T) {
+	// the latitudes are clamped
+	hashes := CoverRectangle(Bounds{
+		Min: Pos{Lat: -100, Lon: 0},
+		Max: Pos{Lat: 10, Lon: 10},
+	}, 2)
+	assert.Equal(t, CoverRectangle(Bounds{
+		Min: Pos{Lat: -90, Lon: 0},
+		Max: Pos{Lat: 10, Lon: 10},
+	}, 2), hashes)
+	assert.Equal(t, 9*2, len(hashes))
+
+	// the longitudes are wrapped, the box crosses the antimeridian
+	hashes = CoverRectangle(Bounds{
+		Min: Pos{Lat: 10, Lon: -181},
+		Max: Pos{Lat: 11, Lon: -179},
+	}, 2)
+	assert.Equal(t, []Hash{
+		mustParse("xc"), mustParse("81"),
+	}, hashes)
+
+	hashes = CoverRectangle(Bounds{
+		Min: Pos{Lat: 10, Lon: -190},
+		Max: Pos{Lat: 11, Lon: 10},
+	}, 1)
+	assert.Equal(t, []Hash{
+		mustParse("x"), mustParse("8"), mustParse("9"), mustParse("d"), mustParse("e"), mustParse("s"),
+	}, hashes)
+
+	// spanning more than the whole longitude range
+	hashes = CoverRectangle(Bounds{
+		Min: Pos{Lat: 10, Lon: -200},
+		Max: Pos{Lat: 11, Lon: 200},
+	}, 1)
+	assert.Equal(t, 8, len(hashes))
+
+	tests := []struct {
+		name   string
+		bounds Bounds
+	}{
+		{name: "above-the-north-pole", bounds: Bounds{Min: Pos{Lat: 91}, Max: Pos{Lat: 95}}},
+		{name: "below-the-south-pole", bounds: Bounds{Min: Pos{Lat: -95}, Max: Pos{Lat: -91}}},
+		{name: "lat-nan", bounds: Bounds{Min: Pos{Lat: math.NaN()}, Max: Pos{Lat: 1}}},
+		{name: "lon-inf", bounds: Bounds{Min: Pos{Lon: math.Inf(-1)}, Max: Pos{Lat: 1}}},
+	}
+	for _, e := range tests {
+		tc := e
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, 0, len(CoverRectangle(tc.bounds, 2)))
+			assert.Equal(t, 0, len(CoverRectangleMaxCells(tc.bounds, 2, 10)))
+		})
+	}
+
+	bounds := Bounds{Min: Pos{Lat: 1, Lon: 1}, Max: Pos{Lat: 1, Lon: 1}}
+	assert.Equal(t, 1, len(CoverRectangle(bounds, MaxPrecision)))
+	assert.Equal(t, []Hash(nil), CoverRectangle(bounds, MaxPrecision+1))
+	assert.Equal(t, []Hash(nil), CoverRectangle(bounds, 0))
+	assert.Equal(t, []Hash(nil), CoverRectangleMaxCells(bounds, 30, 10))
+}
+
+func TestBounds_Contains(t *testing.T) {
+	b := Bounds{
+		Min: Pos{Lat: 10, Lon: 20},
+		Max: Pos{Lat: 11, Lon: 21},
+	}
+	assert.Equal(t, true, b.Contains(Pos{Lat: 10.5, Lon: 20.5}))
+	assert.Equal(t, true, b.Contains(Pos{Lat: 11, Lon: 21}))
+	assert.Equal(t, false, b.Contains(Pos{Lat: 11.1, Lon: 20.5}))
+	assert.Equal(t, false, b.Contains(Pos{Lat: 10.5, Lon: 19.9}))
+
+	b = Bounds{
+		Min: Pos{Lat: 10, Lon: 179},
+		Max: Pos{Lat: 11, Lon: -179},
+	}
+	assert.Equal(t, true, b.Contains(Pos{Lat: 10.5, Lon: 179.5}))
+	assert.Equal(t, true, b.Contains(Pos{Lat: 10.5, Lon: -179.5}))
+	assert.Equal(t, false, b.Contains(Pos{Lat: 10.5, Lon: 0}))
+
+	b = Bounds{
+		Min: Pos{Lat: 10, Lon: -190},
+		Max: Pos{Lat: 11, Lon: -170},
+	}
+	assert.Equal(t, true, b.Contains(Pos{Lat: 10.5, Lon: 175}))
+	assert.Equal(t, true, b.Contains(Pos{Lat: 10.5, Lon: -175}))
+	assert.Equal(t, false, b.Contains(Pos{Lat: 10.5, Lon: 0}))
+}
+
+func TestCoverRectangleMaxCells(t *testing.T) {
+	bounds := Bounds{
+		Min: Pos{Lat: 10.5, Lon: 105.5},
+		Max: Pos{Lat: 11.5, Lon: 106.5},
+	}
+
+	assert.Equal(t, 576, len(CoverRectangle(bounds, 5)))
+
+	hashes := CoverRectangleMaxCells(bounds, 5, 576)
+	assert.Equal(t, CoverRectangle(bounds, 5), hashes)
+
+	hashes = CoverRectangleMaxCells(bounds, 5, 500)
+	assert.Equal(t, CoverRectangle(bounds, 4), hashes)
+	assert.Equal(t, 21, len(hashes))
+
+	hashes = CoverRectangleMaxCells(bounds, 5, 2)
+	assert.Equal(t, CoverRectangle(bounds, 3), hashes)
+	assert.Equal(t, 2, len(hashes))
+
+	hashes = CoverRectangleMaxCells(bounds, 5, 1)
+	assert.Equal(t, []Hash{mustParse("w")}, hashes)
+
+	// no limit
+	hashes = CoverRectangleMaxCells(bounds, 5, 0)
+	assert.Equal(t, CoverRectangle(bounds, 5), hashes)
+
+	tiny := Bounds{
+		Min: Pos{Lat: 10.77, Lon: 106.7},
+		Max: Pos{Lat: 10.77, Lon: 106.7},
+	}
+	hashes = CoverRectangleMaxCells(tiny, 8, -1)
+	assert.Equal(t, []Hash{ComputeGeohash(tiny.Min, 8)}, hashes)
+}
+
+func TestCoverRectangle_Properties_Based_Testing(t *testing.T) {
+	seed := time.Now().Unix()
+	fmt.Println("SEED:", seed)
+	rand.Seed(seed)
+
+	for i := 0; i < 100; i++ {
+		minPos := Pos{
+			Lat: mathRand(-89, 80),
+			Lon: mathRand(-180, 180),
+		}
+		maxPos := Pos{
+			Lat: minPos.Lat + mathRand(0, 2),
+			Lon: minPos.Lon + mathRand(0, 2),
+		}
+		unwrappedMaxLon := maxPos.Lon
+		if maxPos.Lon > 180 {
+			maxPos.Lon -= 360
+		}
+		bounds := Bounds{Min: minPos, Max: maxPos}
+
+		prec := uint32(randInt(1, 4))
+		hashes := CoverRectangle(bounds, prec)
+		set := hashListToStrings(hashes)
+		assert.Equal(t, len(hashes), len(set))
+
+		for k := 0; k < 1000; k++ {
+			lon := mathRand(minPos.Lon, minPos.Lon+2)
+			if lon > unwrappedMaxLon {
+				continue
+			}
+			if lon > 180 {
+				lon -= 360
+			}
+			p := Pos{
+				Lat: mathRand(minPos.Lat, maxPos.Lat),
+				Lon: lon,
+			}
+
+			_, ok := set[ComputeGeohash(p, prec).String()]
+			assert.True(t, ok, p)
+		}
+	}
+}
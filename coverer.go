@@ -0,0 +1,225 @@
+package geohash
+
+import (
+	"math"
+	"sort"
+)
+
+// Region is a shape that can be covered by geohashes of mixed precisions
+type Region interface {
+	// RectBound returns a box containing the whole region
+	RectBound() Bounds
+
+	// IntersectsCell returns false only if the geohash cell does not intersect the region
+	IntersectsCell(h Hash) bool
+
+	// ContainsCell returns true only if the geohash cell is fully inside the region
+	ContainsCell(h Hash) bool
+}
+
+var _ Region = Circle{}
+var _ Region = Bounds{}
+var _ Region = Polygon{}
+
+// Circle is a spherical cap
+type Circle struct {
+	Center Pos
+	Radius float64 // in km
+}
+
+// RectBound returns a box containing the circle
+func (c Circle) RectBound() Bounds {
+	angle := c.Radius / earthRadius
+	degrees := angle * 180 / math.Pi
+
+	minLat := c.Center.Lat - degrees
+	maxLat := c.Center.Lat + degrees
+	if minLat <= -90 || maxLat >= 90 {
+		// contains a pole
+		return Bounds{
+			Min: Pos{Lat: math.Max(minLat, -90), Lon: -180},
+			Max: Pos{Lat: math.Min(maxLat, 90), Lon: 180},
+		}
+	}
+
+	sinLon := math.Sin(angle) / math.Cos(c.Center.Lat*math.Pi/180)
+	if angle >= math.Pi/2 || sinLon >= 1 {
+		return Bounds{
+			Min: Pos{Lat: minLat, Lon: -180},
+			Max: Pos{Lat: maxLat, Lon: 180},
+		}
+	}
+
+	lonDegrees := math.Asin(sinLon) * 180 / math.Pi
+
+	minLon := c.Center.Lon - lonDegrees
+	if minLon < -180 {
+		minLon += 360
+	}
+	maxLon := c.Center.Lon + lonDegrees
+	if maxLon > 180 {
+		maxLon -= 360
+	}
+
+	return Bounds{
+		Min: Pos{Lat: minLat, Lon: minLon},
+		Max: Pos{Lat: maxLat, Lon: maxLon},
+	}
+}
+
+// IntersectsCell checks whether the nearest point of the cell is inside the circle
+func (c Circle) IntersectsCell(h Hash) bool {
+	return minDistanceToGeohash(c.Center, h) <= c.Radius
+}
+
+// ContainsCell checks whether the farthest point of the cell is inside the circle
+func (c Circle) ContainsCell(h Hash) bool {
+	return maxDistanceToGeohash(c.Center, h) <= c.Radius
+}
+
+// RectBound returns the bounds itself
+func (b Bounds) RectBound() Bounds {
+	return b
+}
+
+// IntersectsCell checks whether the cell and the bounds overlap,
+// a cell contains its bottom and left edges but not its top and right edges,
+// except the top row and the last column, the same as ComputeGeohash clamps lat = 90 and lon = 180
+func (b Bounds) IntersectsCell(h Hash) bool {
+	b, ok := b.normalize()
+	if !ok {
+		return false
+	}
+
+	box := h.Bounds()
+	if !(box.Max.Lat > b.Min.Lat || box.Max.Lat == 90) || box.Min.Lat > b.Max.Lat {
+		return false
+	}
+
+	rightOfMin := box.Max.Lon > b.Min.Lon || box.Max.Lon == 180
+	if b.Min.Lon <= b.Max.Lon {
+		return rightOfMin && box.Min.Lon <= b.Max.Lon
+	}
+	// crossing the antimeridian
+	return rightOfMin || box.Min.Lon <= b.Max.Lon
+}
+
+// ContainsCell checks whether the cell is fully inside the bounds
+func (b Bounds) ContainsCell(h Hash) bool {
+	b, ok := b.normalize()
+	if !ok {
+		return false
+	}
+
+	box := h.Bounds()
+	if box.Min.Lat < b.Min.Lat || box.Max.Lat > b.Max.Lat {
+		return false
+	}
+
+	if b.Min.Lon <= b.Max.Lon {
+		return box.Min.Lon >= b.Min.Lon && box.Max.Lon <= b.Max.Lon
+	}
+	// crossing the antimeridian
+	return box.Min.Lon >= b.Min.Lon || box.Max.Lon <= b.Max.Lon
+}
+
+// RectBound returns the bounding box of the exterior ring
+func (p Polygon) RectBound() Bounds {
+	return p.bounds()
+}
+
+// IntersectsCell checks whether the cell intersects the polygon
+func (p Polygon) IntersectsCell(h Hash) bool {
+	box := h.Bounds()
+	return p.intersectsEdges(box) || p.Contains(boundsCenter(box))
+}
+
+// ContainsCell checks whether the cell is fully inside the polygon
+func (p Polygon) ContainsCell(h Hash) bool {
+	box := h.Bounds()
+	return !p.intersectsEdges(box) && p.Contains(boundsCenter(box))
+}
+
+// CoverRegion computes a covering of geohashes with precisions in [minPrecision, maxPrecision].
+// It starts from cells at minPrecision and only subdivides the cells on the boundary of the region,
+// coarser cells first, while the number of cells stays not greater than maxCells (maxCells <= 0 means no limit).
+// The covering at minPrecision is returned even if it has more than maxCells cells.
+// Both precisions are clamped into [1, MaxPrecision]. The result is sorted and the cells do not overlap
+func CoverRegion(region Region, minPrecision uint32, maxPrecision uint32, maxCells int) []Hash {
+	if minPrecision == 0 {
+		minPrecision = 1
+	}
+	if minPrecision > MaxPrecision {
+		minPrecision = MaxPrecision
+	}
+	if maxPrecision > MaxPrecision {
+		maxPrecision = MaxPrecision
+	}
+	if maxPrecision < minPrecision {
+		maxPrecision = minPrecision
+	}
+
+	var result []Hash
+	var queue []Hash
+
+	for _, h := range CoverRectangle(region.RectBound(), minPrecision) {
+		if region.ContainsCell(h) {
+			result = append(result, h)
+		} else if region.IntersectsCell(h) {
+			queue = append(queue, h)
+		}
+	}
+
+	// the queue is ordered by precision, because children are appended after their parents
+	for len(queue) > 0 {
+		h := queue[0]
+		queue = queue[1:]
+
+		if h.bits >= maxPrecision*5 {
+			result = append(result, h)
+			continue
+		}
+
+		var children []Hash
+		for _, child := range h.Children() {
+			if region.IntersectsCell(child) {
+				children = append(children, child)
+			}
+		}
+
+		if maxCells > 0 && len(result)+len(queue)+len(children) > maxCells {
+			result = append(result, h)
+			continue
+		}
+
+		for _, child := range children {
+			if region.ContainsCell(child) {
+				result = append(result, child)
+			} else {
+				queue = append(queue, child)
+			}
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return lessHash(result[i], result[j])
+	})
+	return result
+}
+
+// lessHash is the order of geohash strings, parents before their children
+func lessHash(a, b Hash) bool {
+	bitCount := a.bits
+	if b.bits < bitCount {
+		bitCount = b.bits
+	}
+
+	for index := uint32(0); index < bitCount; index++ {
+		bitA := a.bitAt(index)
+		bitB := b.bitAt(index)
+		if bitA != bitB {
+			return bitA < bitB
+		}
+	}
+	return a.bits < b.bits
+}
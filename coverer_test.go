@@ -0,0 +1,233 @@
+package geohash
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func isCoveredBy(pos Pos, hashes []Hash) bool {
+	for _, h := range hashes {
+		if ComputeGeohashBits(pos, h.bits) == h {
+			return true
+		}
+	}
+	return false
+}
+
+func assertValidCovering(t *testing.T, hashes []Hash, minPrecision, maxPrecision uint32) {
+	t.Helper()
+
+	for i, h := range hashes {
+		assert.GreaterOrEqual(t, h.bits, minPrecision*5)
+		assert.LessOrEqual(t, h.bits, maxPrecision*5)
+
+		if i > 0 {
+			prev := hashes[i-1]
+			assert.True(t, lessHash(prev, h))
+			if prev.bits <= h.bits {
+				assert.NotEqual(t, prev, h.truncate(prev.bits), "overlapped cells")
+			}
+		}
+	}
+}
+
+func wrapLon(lon float64) float64 {
+	if lon > 180 {
+		return lon - 360
+	}
+	if lon < -180 {
+		return lon + 360
+	}
+	return lon
+}
+
+func TestCircle_RectBound(t *testing.T) {
+	b := Circle{
+		Center: Pos{Lat: 0, Lon: 0},
+		Radius: 111.19508372419141,
+	}.RectBound()
+	assert.InDelta(t, -1, b.Min.Lat, 1e-9)
+	assert.InDelta(t, -1, b.Min.Lon, 1e-9)
+	assert.InDelta(t, 1, b.Max.Lat, 1e-9)
+	assert.InDelta(t, 1, b.Max.Lon, 1e-9)
+
+	b = Circle{
+		Center: Pos{Lat: 60, Lon: 179.5},
+		Radius: 111.19508372419141,
+	}.RectBound()
+	assert.InDelta(t, 59, b.Min.Lat, 1e-9)
+	assert.InDelta(t, 177.5, b.Min.Lon, 0.01)
+	assert.InDelta(t, 61, b.Max.Lat, 1e-9)
+	assert.InDelta(t, -178.5, b.Max.Lon, 0.01)
+
+	b = Circle{
+		Center: Pos{Lat: 89.5, Lon: 10},
+		Radius: 111.19508372419141,
+	}.RectBound()
+	assert.Equal(t, Bounds{
+		Min: Pos{Lat: 88.5, Lon: -180},
+		Max: Pos{Lat: 90, Lon: 180},
+	}, b)
+}
+
+func TestMaxDistanceToGeohash(t *testing.T) {
+	h := mustParse("s00")
+	origin := Pos{Lat: 0.7, Lon: 0.7}
+
+	rec := h.Rec()
+	assert.InDelta(t, haversineDistance(origin, rec.TopRight), maxDistanceToGeohash(origin, h), 1e-6)
+
+	origin = Pos{Lat: -2, Lon: -3}
+	assert.InDelta(t, haversineDistance(origin, rec.TopRight), maxDistanceToGeohash(origin, h), 1e-6)
+
+	// the farthest point is inside the bottom edge, the nearest point to the antipode (-0.7, 1)
+	origin = Pos{Lat: 0.7, Lon: -179}
+	assert.InDelta(t, haversineDistance(origin, Pos{Lat: 0, Lon: 1}), maxDistanceToGeohash(origin, h), 1e-6)
+}
+
+func TestMinDistanceToGeohash_Inside_And_Antimeridian(t *testing.T) {
+	h := mustParse("s00")
+	assert.Equal(t, 0.0, minDistanceToGeohash(Pos{Lat: 0.7, Lon: 0.7}, h))
+
+	h = ComputeGeohash(Pos{Lat: 10, Lon: 179.9}, 5)
+	origin := Pos{Lat: 10, Lon: -179.9}
+	right := h.Bounds().Max.Lon
+	assert.InDelta(t, haversineDistance(origin, Pos{Lat: 10, Lon: right}), minDistanceToGeohash(origin, h), 1e-3)
+
+	// the nearest point is the top left corner, not the point on the left meridian at MinLatDistance
+	h = mustParse("s00")
+	origin = Pos{Lat: 30, Lon: -120}
+	assert.Equal(t, haversineDistance(origin, Pos{Lat: 1.40625, Lon: 0}), minDistanceToGeohash(origin, h))
+}
+
+func TestCoverRegion_Circle(t *testing.T) {
+	circle := Circle{
+		Center: Pos{Lat: 10.7769, Lon: 106.7009},
+		Radius: 5,
+	}
+
+	hashes := CoverRegion(circle, 3, 7, 50)
+	assert.LessOrEqual(t, len(hashes), 50)
+	assertValidCovering(t, hashes, 3, 7)
+
+	hashes = CoverRegion(circle, 5, 5, 0)
+	assert.Equal(t, hashListToStrings(NearbyGeohashList(circle.Center, circle.Radius, 5)), hashListToStrings(hashes))
+}
+
+func TestCoverRegion_Bounds(t *testing.T) {
+	bounds := Bounds{
+		Min: Pos{Lat: 0, Lon: 0},
+		Max: Pos{Lat: 46, Lon: 44.9},
+	}
+
+	hashes := CoverRegion(bounds, 1, 1, 0)
+	assert.Equal(t, []Hash{mustParse("s"), mustParse("u")}, hashes)
+
+	hashes = CoverRegion(bounds, 1, 2, 0)
+	assert.Equal(t, 36, len(hashes))
+	assert.Equal(t, mustParse("s").Children(), hashes[:32])
+	assert.Equal(t, []Hash{
+		mustParse("u0"), mustParse("u2"), mustParse("u8"), mustParse("ub"),
+	}, hashes[32:])
+
+	hashes = CoverRegion(bounds, 1, 2, 5)
+	assert.Equal(t, []Hash{
+		mustParse("s"),
+		mustParse("u0"), mustParse("u2"), mustParse("u8"), mustParse("ub"),
+	}, hashes)
+
+	// the top and right edges of the cells are excluded
+	hashes = CoverRegion(Bounds{
+		Min: Pos{Lat: 0, Lon: 0},
+		Max: Pos{Lat: 44.9, Lon: 44.9},
+	}, 1, 1, 0)
+	assert.Equal(t, []Hash{mustParse("s")}, hashes)
+
+	// the top row and the last column contain their top and right edges
+	for _, b := range []Bounds{
+		{Min: Pos{Lat: 90, Lon: 0}, Max: Pos{Lat: 90, Lon: 1}},
+		{Min: Pos{Lat: 0, Lon: 180}, Max: Pos{Lat: 1, Lon: 180}},
+		{Min: Pos{Lat: 90, Lon: 180}, Max: Pos{Lat: 90, Lon: 180}},
+	} {
+		assert.Equal(t, CoverRectangle(b, 3), CoverRegion(b, 2, 3, 0))
+		assert.Equal(t, 1, len(CoverRegion(b, 2, 3, 0)))
+	}
+
+	// the longitudes are wrapped the same as CoverRectangle
+	hashes = CoverRegion(Bounds{
+		Min: Pos{Lat: 10, Lon: -190},
+		Max: Pos{Lat: 11, Lon: -170},
+	}, 1, 1, 0)
+	assert.Equal(t, []Hash{mustParse("8"), mustParse("x")}, hashes)
+
+	// the precisions are clamped into [1, MaxPrecision]
+	point := Bounds{Min: Pos{Lat: 1, Lon: 1}, Max: Pos{Lat: 1, Lon: 1}}
+	hashes = CoverRegion(point, 25, 30, 0)
+	assert.Equal(t, []Hash{ComputeGeohash(point.Min, MaxPrecision)}, hashes)
+}
+
+func TestCoverRegion_Polygon(t *testing.T) {
+	polygon := Polygon{
+		Exterior: squareRing(0.1, 4.1),
+	}
+
+	hashes := CoverRegion(polygon, 2, 3, 0)
+	assert.Equal(t, CoverRegion(polygon, 3, 3, 0), hashes)
+	assert.Equal(t, 9, len(hashes))
+
+	hashes = CoverRegion(polygon, 2, 6, 200)
+	assert.LessOrEqual(t, len(hashes), 200)
+	assertValidCovering(t, hashes, 2, 6)
+}
+
+func TestCoverRegion_Properties_Based_Testing(t *testing.T) {
+	seed := time.Now().Unix()
+	fmt.Println("SEED:", seed)
+	rand.Seed(seed)
+
+	for i := 0; i < 30; i++ {
+		center := Pos{
+			Lat: mathRand(-70, 70),
+			Lon: mathRand(-180, 180),
+		}
+
+		var ring []Pos
+		for k := 0; k < randInt(3, 8); k++ {
+			ring = append(ring, Pos{
+				Lat: center.Lat + mathRand(-1, 1),
+				Lon: center.Lon + mathRand(-1, 1),
+			})
+		}
+
+		regions := []Region{
+			Circle{Center: center, Radius: mathRand(1, 100)},
+			Bounds{
+				Min: Pos{Lat: center.Lat - 1, Lon: wrapLon(center.Lon - 1)},
+				Max: Pos{Lat: center.Lat + 1, Lon: wrapLon(center.Lon + 1)},
+			},
+		}
+		if center.Lon > -179 && center.Lon < 179 {
+			regions = append(regions, Polygon{Exterior: ring})
+		}
+
+		for _, region := range regions {
+			maxCells := randInt(10, 100)
+			hashes := CoverRegion(region, 2, 8, maxCells)
+			assertValidCovering(t, hashes, 2, 8)
+
+			for k := 0; k < 1000; k++ {
+				p := Pos{
+					Lat: center.Lat + mathRand(-1, 1),
+					Lon: wrapLon(center.Lon + mathRand(-1, 1)),
+				}
+
+				if region.ContainsCell(ComputeGeohash(p, 10)) {
+					assert.True(t, isCoveredBy(p, hashes), p)
+				}
+			}
+		}
+	}
+}
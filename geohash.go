@@ -2,20 +2,68 @@ package geohash
 
 import (
 	"encoding/binary"
+	"errors"
+	"fmt"
 	"github.com/QuangTung97/haversine"
 	"math"
+	"sort"
 )
 
+const (
+	// MaxPrecision is the maximum number of characters of a geohash.
+	// The integer encodings do not support every precision: Hash.Uint64 and ComputeRanges
+	// support at most 64 bits (12 characters), Hash.Int64, MarshalBinary and Int64Hash at most
+	// MaxPackedBitPrecision bits, finer geohashes are only supported by the string encodings
+	MaxPrecision = 20
+
+	// MaxBitPrecision is the maximum number of interleaved bits of a geohash,
+	// it keeps both lat bits and lon bits exactly representable by a float64
+	MaxBitPrecision = MaxPrecision * 5
+)
+
+var (
+	// ErrEmptyGeohash is returned when parsing an empty geohash string
+	ErrEmptyGeohash = errors.New("geohash: empty geohash string")
+
+	// ErrGeohashTooLong is returned when parsing a geohash string longer than MaxPrecision
+	ErrGeohashTooLong = errors.New("geohash: geohash string is too long")
+
+	// ErrInvalidLatitude is returned when a latitude is NaN or outside of [-90, 90]
+	ErrInvalidLatitude = errors.New("geohash: invalid latitude")
+
+	// ErrInvalidLongitude is returned when a longitude is NaN or outside of [-180, 180]
+	ErrInvalidLongitude = errors.New("geohash: invalid longitude")
+
+	// ErrInvalidPrecision is returned when a precision is zero or greater than MaxPrecision,
+	// or a bit precision is zero or greater than MaxBitPrecision
+	ErrInvalidPrecision = errors.New("geohash: invalid precision")
+
+	// ErrValueOutOfRange is returned when an integer geohash has more bits than its precision
+	ErrValueOutOfRange = errors.New("geohash: integer geohash out of range")
+)
+
+// InvalidCharacterError is returned when parsing a geohash string containing
+// a character that is not in the base32 geohash alphabet
+type InvalidCharacterError struct {
+	Char  byte
+	Index int
+}
+
+func (e *InvalidCharacterError) Error() string {
+	return fmt.Sprintf("geohash: invalid character %q at index %d", e.Char, e.Index)
+}
+
 type Pos struct {
 	Lat float64 // in degree
 	Lon float64 // in degree
 }
 
-// Hash ...
+// Hash is a geohash of up to MaxBitPrecision interleaved bits.
+// Geohashes finer than 64 bits can not be converted to integers, see MaxPrecision
 type Hash struct {
-	precision uint32
-	lat       uint32 // lat bits
-	lon       uint32 // lon bits
+	bits uint32 // number of interleaved bits
+	lat  uint64 // lat bits
+	lon  uint64 // lon bits
 }
 
 // Rectangle represents all corners
@@ -50,37 +98,201 @@ func spacing(bits uint64, count uint32) uint64 {
 	return binary.LittleEndian.Uint64(bytes[:])
 }
 
+// unspacing is the inverse of spacing, collects count bits at the even positions
+func unspacing(bits uint64, count uint32) uint64 {
+	var result uint64
+	for index := uint32(0); index < count; index++ {
+		result |= ((bits >> (2 * index)) & 1) << index
+	}
+	return result
+}
+
+// bitPrecisions returns the number of lat bits and lon bits,
+// lon gets the extra bit when the number of interleaved bits is odd
+func bitPrecisions(bitCount uint32) (latPrecision uint32, lonPrecision uint32) {
+	latPrecision = bitCount >> 1
+	lonPrecision = bitCount - latPrecision
+	return latPrecision, lonPrecision
+}
+
+// bitAt returns the interleaved bit at the index, counting from the most significant bit.
+// The interleaved bits start with a lon bit and then alternate between lat and lon
+func (h Hash) bitAt(index uint32) uint8 {
+	latPrecision, lonPrecision := bitPrecisions(h.bits)
+	if index%2 == 0 {
+		return uint8(h.lon>>(lonPrecision-1-index/2)) & 1
+	}
+	return uint8(h.lat>>(latPrecision-1-index/2)) & 1
+}
+
+// String returns the base32 geohash string. It is only defined when the bit precision
+// is a multiple of 5, otherwise it returns an empty string
 func (h Hash) String() string {
-	bitCount := h.precision * 5
-	latPrecision := bitCount >> 1
-	lonPrecision := bitCount - latPrecision
+	if h.bits%5 != 0 {
+		return ""
+	}
+
+	precision := h.bits / 5
+
+	stringBytes := make([]byte, 0, precision)
 
-	latBits := spacing(uint64(h.lat), latPrecision)
-	lonBits := spacing(uint64(h.lon), lonPrecision)
+	var value uint8
+	for index := uint32(0); index < precision*5; index++ {
+		value = value<<1 | h.bitAt(index)
+		if index%5 == 4 {
+			stringBytes = append(stringBytes, encoding[value])
+			value = 0
+		}
+	}
+
+	return string(stringBytes)
+}
+
+// Uint64 returns the interleaved bits as an integer, the same bits as String() encodes.
+// Returns ErrInvalidPrecision if the bit precision is greater than 64
+func (h Hash) Uint64() (uint64, error) {
+	if h.bits > 64 {
+		return 0, ErrInvalidPrecision
+	}
+
+	latPrecision, lonPrecision := bitPrecisions(h.bits)
+
+	latBits := spacing(h.lat, latPrecision)
+	lonBits := spacing(h.lon, lonPrecision)
+
+	if latPrecision == lonPrecision {
+		return latBits | (lonBits << 1), nil
+	}
+	return (latBits << 1) | lonBits, nil
+}
 
-	var resultHash uint64
+// FromUint64 is the inverse of Hash.Uint64(), support bitPrecision in [1, 64]
+func FromUint64(value uint64, bitPrecision uint32) (Hash, error) {
+	if bitPrecision == 0 || bitPrecision > 64 {
+		return Hash{}, ErrInvalidPrecision
+	}
+	if bitPrecision < 64 && value>>bitPrecision != 0 {
+		return Hash{}, ErrValueOutOfRange
+	}
 
+	latPrecision, lonPrecision := bitPrecisions(bitPrecision)
+
+	h := Hash{bits: bitPrecision}
 	if latPrecision == lonPrecision {
-		resultHash = latBits | (lonBits << 1)
+		h.lat = unspacing(value, latPrecision)
+		h.lon = unspacing(value>>1, lonPrecision)
 	} else {
-		resultHash = (latBits << 1) | lonBits
+		h.lat = unspacing(value>>1, latPrecision)
+		h.lon = unspacing(value, lonPrecision)
 	}
+	return h, nil
+}
 
-	var resultBytes [12]uint8
-	for index := uint32(0); index < h.precision; index++ {
-		resultBytes[index] = uint8(resultHash & 0b11111)
-		resultHash >>= 5
+// truncate keeps only the leading bitCount interleaved bits
+func (h Hash) truncate(bitCount uint32) Hash {
+	latPrecision, lonPrecision := bitPrecisions(h.bits)
+	newLatPrecision, newLonPrecision := bitPrecisions(bitCount)
+
+	return Hash{
+		bits: bitCount,
+		lat:  h.lat >> (latPrecision - newLatPrecision),
+		lon:  h.lon >> (lonPrecision - newLonPrecision),
 	}
+}
 
-	stringBytes := make([]byte, 0, 12)
-	for i := int(h.precision - 1); i >= 0; i-- {
-		b := encoding[resultBytes[i]]
-		stringBytes = append(stringBytes, b)
+// appendBits appends count interleaved bits of the value, from the most significant bit
+func (h Hash) appendBits(value uint64, count uint32) Hash {
+	for index := count; index > 0; index-- {
+		bit := (value >> (index - 1)) & 1
+		if h.bits%2 == 0 {
+			h.lon = h.lon<<1 | bit
+		} else {
+			h.lat = h.lat<<1 | bit
+		}
+		h.bits++
+	}
+	return h
+}
+
+// Children returns 32 sub cells in the order of their geohash strings.
+// Returns nil if the sub cells would exceed MaxBitPrecision
+func (h Hash) Children() []Hash {
+	if h.bits+5 > MaxBitPrecision {
+		return nil
 	}
 
-	return string(stringBytes)
+	result := make([]Hash, 0, len(encoding))
+	for value := range encoding {
+		result = append(result, h.appendBits(uint64(value), 5))
+	}
+	return result
+}
+
+// MaxChildrenDepth is the maximum number of characters added by ChildrenAt, at most 32^4 (about a million) sub cells
+const MaxChildrenDepth = 4
+
+// ChildrenAt returns all sub cells at the precision (in characters), in the order of their geohash strings.
+// There are 32^(precision - h.Precision()) sub cells.
+// Returns nil if the precision is coarser than the geohash, greater than MaxPrecision
+// or more than MaxChildrenDepth characters finer than the geohash
+func (h Hash) ChildrenAt(precision uint32) []Hash {
+	bitCount := precision * 5
+	if bitCount < h.bits || precision > MaxPrecision || bitCount-h.bits > MaxChildrenDepth*5 {
+		return nil
+	}
+
+	result := []Hash{h}
+	for result[0].bits < bitCount {
+		count := uint32(5)
+		if remain := bitCount - result[0].bits; remain < count {
+			count = remain
+		}
+
+		next := make([]Hash, 0, len(result)<<count)
+		for _, parent := range result {
+			for value := uint64(0); value < 1<<count; value++ {
+				next = append(next, parent.appendBits(value, count))
+			}
+		}
+		result = next
+	}
+	return result
 }
 
+// Parent returns the geohash with one character less.
+// If the number of bits is not a multiple of 5, the bits are truncated to the previous multiple of 5.
+// The parent of an empty geohash is itself
+func (h Hash) Parent() Hash {
+	if h.bits == 0 {
+		return h
+	}
+	return h.truncate((h.bits - 1) / 5 * 5)
+}
+
+// Contains checks whether the other geohash is the same as or inside this geohash
+func (h Hash) Contains(other Hash) bool {
+	if other.bits < h.bits {
+		return false
+	}
+	return other.truncate(h.bits) == h
+}
+
+// IsAncestorOf checks whether the other geohash is strictly inside this geohash
+func (h Hash) IsAncestorOf(other Hash) bool {
+	return other.bits > h.bits && h.Contains(other)
+}
+
+// Precision returns the number of characters of the geohash string
+func (h Hash) Precision() uint32 {
+	return h.bits / 5
+}
+
+// BitPrecision returns the number of interleaved bits
+func (h Hash) BitPrecision() uint32 {
+	return h.bits
+}
+
+// Left returns the geohash on the left, wrapping around the antimeridian
 func (h Hash) Left() Hash {
 	return h.addOffset(posOffset{
 		lat: 0,
@@ -88,6 +300,7 @@ func (h Hash) Left() Hash {
 	})
 }
 
+// Right returns the geohash on the right, wrapping around the antimeridian
 func (h Hash) Right() Hash {
 	return h.addOffset(posOffset{
 		lat: 0,
@@ -95,43 +308,119 @@ func (h Hash) Right() Hash {
 	})
 }
 
+// Top returns the geohash above. For a geohash touching the north pole,
+// it returns the geohash across the pole: the same row, 180 degrees of longitude away
 func (h Hash) Top() Hash {
-	return h.addOffset(posOffset{
-		lat: 1,
-		lon: 0,
-	})
+	result, ok := h.Offset(1, 0)
+	if !ok {
+		return h.acrossThePole()
+	}
+	return result
 }
 
+// Bottom returns the geohash below. For a geohash touching the south pole,
+// it returns the geohash across the pole: the same row, 180 degrees of longitude away
 func (h Hash) Bottom() Hash {
+	result, ok := h.Offset(-1, 0)
+	if !ok {
+		return h.acrossThePole()
+	}
+	return result
+}
+
+// Offset moves the geohash by latSteps rows and lonSteps columns, the longitude wraps around the antimeridian.
+// Returns false if the result would cross the north or the south pole
+func (h Hash) Offset(latSteps int, lonSteps int) (Hash, bool) {
+	latPrecision, _ := bitPrecisions(h.bits)
+
+	lat := int64(h.lat) + int64(latSteps)
+	if lat < 0 || lat >= int64(1)<<latPrecision {
+		return Hash{}, false
+	}
+
 	return h.addOffset(posOffset{
-		lat: -1,
-		lon: 0,
+		lat: latSteps,
+		lon: lonSteps,
+	}), true
+}
+
+func (h Hash) acrossThePole() Hash {
+	_, lonPrecision := bitPrecisions(h.bits)
+	return h.addOffset(posOffset{
+		lat: 0,
+		lon: 1 << lonPrecision >> 1,
 	})
 }
 
 // Pos returns the bottom left position of this geohash
 func (h Hash) Pos() Pos {
-	bitCount := h.precision * 5
-	latPrecision := bitCount >> 1
-	lonPrecision := bitCount - latPrecision
+	latPrecision, lonPrecision := bitPrecisions(h.bits)
 
-	lat := bitsToLat(h.lat, uint32(1<<latPrecision))
-	lon := bitsToLon(h.lon, uint32(1<<lonPrecision))
+	lat := bitsToLat(h.lat, uint64(1)<<latPrecision)
+	lon := bitsToLon(h.lon, uint64(1)<<lonPrecision)
 
 	return Pos{Lat: lat, Lon: lon}
 }
 
-// Rec returns 4 corners of this geohash
+// Bounds computes the bottom left and top right positions arithmetically,
+// the right edge of the last column is at longitude 180 and the top edge of the last row is at latitude 90
+func (h Hash) Bounds() Bounds {
+	latPrecision, lonPrecision := bitPrecisions(h.bits)
+
+	return Bounds{
+		Min: h.Pos(),
+		Max: Pos{
+			Lat: bitsToLat(h.lat+1, uint64(1)<<latPrecision),
+			Lon: bitsToLon(h.lon+1, uint64(1)<<lonPrecision),
+		},
+	}
+}
+
+// Rec returns 4 corners of this geohash, the right edge of the last column is at longitude 180
 func (h Hash) Rec() Rectangle {
-	top := h.Top()
+	b := h.Bounds()
 	return Rectangle{
-		BottomLeft:  h.Pos(),
-		BottomRight: h.Right().Pos(),
-		TopLeft:     top.Pos(),
-		TopRight:    top.Right().Pos(),
+		BottomLeft:  b.Min,
+		BottomRight: Pos{Lat: b.Min.Lat, Lon: b.Max.Lon},
+		TopLeft:     Pos{Lat: b.Max.Lat, Lon: b.Min.Lon},
+		TopRight:    b.Max,
 	}
 }
 
+// rectangleCorners returns the closed counterclockwise ring of the rectangle
+func rectangleCorners(r Rectangle) []Pos {
+	return []Pos{r.BottomLeft, r.BottomRight, r.TopRight, r.TopLeft, r.BottomLeft}
+}
+
+// Center returns the center position of this geohash
+func (h Hash) Center() Pos {
+	return boundsCenter(h.Bounds())
+}
+
+// WidthKm returns the haversine distance between the middles of the left and right edges
+func (h Hash) WidthKm() float64 {
+	b := h.Bounds()
+	lat := (b.Min.Lat + b.Max.Lat) / 2
+	return haversineDistance(Pos{Lat: lat, Lon: b.Min.Lon}, Pos{Lat: lat, Lon: b.Max.Lon})
+}
+
+// HeightKm returns the haversine distance between the bottom and top edges
+func (h Hash) HeightKm() float64 {
+	b := h.Bounds()
+	return haversineDistance(b.Min, Pos{Lat: b.Max.Lat, Lon: b.Min.Lon})
+}
+
+// AreaKm2 returns the area of this geohash on the sphere, in square km
+func (h Hash) AreaKm2() float64 {
+	b := h.Bounds()
+
+	lonRadians := (b.Max.Lon - b.Min.Lon) * math.Pi / 180
+	minLat := b.Min.Lat * math.Pi / 180
+	maxLat := b.Max.Lat * math.Pi / 180
+
+	return earthRadius * earthRadius * lonRadians * (math.Sin(maxLat) - math.Sin(minLat))
+}
+
 var encoding = []byte{
 	'0', '1', '2', '3',
 	'4', '5', '6', '7',
@@ -143,36 +432,136 @@ var encoding = []byte{
 	'w', 'x', 'y', 'z',
 }
 
-func lonToBits(lon float64, multiplier uint32) uint32 {
-	return uint32((lon + 180) * float64(multiplier) / 360)
+var decoding = func() [256]int8 {
+	var result [256]int8
+	for i := range result {
+		result[i] = -1
+	}
+	for i, c := range encoding {
+		result[c] = int8(i)
+	}
+	return result
+}()
+
+// Parse decodes a geohash string, the inverse of Hash.String()
+func Parse(s string) (Hash, error) {
+	if len(s) == 0 {
+		return Hash{}, ErrEmptyGeohash
+	}
+	if len(s) > MaxPrecision {
+		return Hash{}, ErrGeohashTooLong
+	}
+
+	var lat, lon uint64
+	bitIndex := 0
+	for i := 0; i < len(s); i++ {
+		value := decoding[s[i]]
+		if value < 0 {
+			return Hash{}, &InvalidCharacterError{Char: s[i], Index: i}
+		}
+
+		// the first bit of every pair is a lon bit
+		for shift := 4; shift >= 0; shift-- {
+			bit := uint64(value>>shift) & 1
+			if bitIndex%2 == 0 {
+				lon = lon<<1 | bit
+			} else {
+				lat = lat<<1 | bit
+			}
+			bitIndex++
+		}
+	}
+
+	return Hash{
+		bits: uint32(len(s)) * 5,
+		lat:  lat,
+		lon:  lon,
+	}, nil
+}
+
+// lonToBits clamps lon = 180 into the last column of the grid
+func lonToBits(lon float64, multiplier uint64) uint64 {
+	bits := uint64((lon + 180) * float64(multiplier) / 360)
+	if bits >= multiplier {
+		return multiplier - 1
+	}
+	return bits
 }
 
-func bitsToLon(bits uint32, multiplier uint32) float64 {
+func bitsToLon(bits uint64, multiplier uint64) float64 {
 	return float64(bits)*360/float64(multiplier) - 180
 }
 
-func latToBits(lat float64, multiplier uint32) uint32 {
-	return uint32((lat + 90) * float64(multiplier) / 180)
+// latToBits clamps lat = 90 into the last row of the grid
+func latToBits(lat float64, multiplier uint64) uint64 {
+	bits := uint64((lat + 90) * float64(multiplier) / 180)
+	if bits >= multiplier {
+		return multiplier - 1
+	}
+	return bits
 }
 
-func bitsToLat(bits uint32, multiplier uint32) float64 {
+func bitsToLat(bits uint64, multiplier uint64) float64 {
 	return float64(bits)*180/float64(multiplier) - 90
 }
 
-// ComputeGeohash support precision <= 12
+// ComputeGeohash support precision <= MaxPrecision.
+// The inputs are NOT validated, use ComputeGeohashChecked for untrusted inputs.
+// The boundary values lat = 90 and lon = 180 are clamped into the top row
+// and the rightmost column of the grid respectively.
 func ComputeGeohash(pos Pos, precision uint32) Hash {
-	bitCount := precision * 5
-	latPrecision := bitCount >> 1
-	lonPrecision := bitCount - latPrecision
+	return ComputeGeohashBits(pos, precision*5)
+}
+
+// ComputeGeohashBits computes the geohash with the number of interleaved bits,
+// support bitPrecision in [1, MaxBitPrecision].
+// The inputs are NOT validated, use ComputeGeohashBitsChecked for untrusted inputs
+func ComputeGeohashBits(pos Pos, bitPrecision uint32) Hash {
+	latPrecision, lonPrecision := bitPrecisions(bitPrecision)
 
-	lat := latToBits(pos.Lat, uint32(1<<latPrecision))
-	lon := lonToBits(pos.Lon, uint32(1<<lonPrecision))
+	lat := latToBits(pos.Lat, uint64(1)<<latPrecision)
+	lon := lonToBits(pos.Lon, uint64(1)<<lonPrecision)
 
 	return Hash{
-		precision: precision,
-		lat:       lat,
-		lon:       lon,
+		bits: bitPrecision,
+		lat:  lat,
+		lon:  lon,
+	}
+}
+
+// ComputeGeohashChecked is the same as ComputeGeohash but returns an error
+// for NaN or out of range coordinates and for precision outside of [1, MaxPrecision]
+func ComputeGeohashChecked(pos Pos, precision uint32) (Hash, error) {
+	if precision == 0 || precision > MaxPrecision {
+		return Hash{}, ErrInvalidPrecision
+	}
+	if err := validatePos(pos); err != nil {
+		return Hash{}, err
+	}
+	return ComputeGeohash(pos, precision), nil
+}
+
+// ComputeGeohashBitsChecked is the same as ComputeGeohashBits but returns an error
+// for NaN or out of range coordinates and for bit precision outside of [1, MaxBitPrecision]
+func ComputeGeohashBitsChecked(pos Pos, bitPrecision uint32) (Hash, error) {
+	if bitPrecision == 0 || bitPrecision > MaxBitPrecision {
+		return Hash{}, ErrInvalidPrecision
+	}
+	if err := validatePos(pos); err != nil {
+		return Hash{}, err
+	}
+	return ComputeGeohashBits(pos, bitPrecision), nil
+}
+
+func validatePos(pos Pos) error {
+	// comparisons with NaN are always false
+	if !(pos.Lat >= -90 && pos.Lat <= 90) {
+		return ErrInvalidLatitude
 	}
+	if !(pos.Lon >= -180 && pos.Lon <= 180) {
+		return ErrInvalidLongitude
+	}
+	return nil
 }
 
 func (p Pos) toHaversine() haversine.Pos {
@@ -182,19 +571,62 @@ func (p Pos) toHaversine() haversine.Pos {
 	}
 }
 
+// earthRadius in km, the same as the one used by haversine.DistanceEarth
+const earthRadius = 6371.009
+
 func haversineDistance(a, b Pos) float64 {
 	return haversine.DistanceEarth(a.toHaversine(), b.toHaversine())
 }
 
+// maxDistanceToGeohash uses the farthest point from the origin being the nearest point from its antipode
+func maxDistanceToGeohash(origin Pos, hash Hash) float64 {
+	antipode := Pos{
+		Lat: -origin.Lat,
+		Lon: origin.Lon + 180,
+	}
+	return math.Pi*earthRadius - minDistanceToGeohash(antipode, hash)
+}
+
 func minDistanceToGeohash(origin Pos, hash Hash) float64 {
-	rec := hash.Rec()
+	box := hash.Bounds()
+
+	// move the origin to the same side of the antimeridian as the geohash
+	center := (box.Min.Lon + box.Max.Lon) / 2
+	for origin.Lon-center > 180 {
+		origin.Lon -= 360
+	}
+	for origin.Lon-center < -180 {
+		origin.Lon += 360
+	}
+
+	if origin.Lat >= box.Min.Lat && origin.Lat <= box.Max.Lat &&
+		origin.Lon >= box.Min.Lon && origin.Lon <= box.Max.Lon {
+		return 0
+	}
+
+	rec := Rectangle{
+		BottomLeft:  box.Min,
+		BottomRight: Pos{Lat: box.Min.Lat, Lon: box.Max.Lon},
+		TopLeft:     Pos{Lat: box.Max.Lat, Lon: box.Min.Lon},
+		TopRight:    box.Max,
+	}
 
 	if origin.Lon < rec.TopLeft.Lon {
-		return haversineDistance(origin, nearestLeftEdge(origin, rec))
+		d := haversineDistance(origin, nearestLeftEdge(origin, rec))
+		if rec.TopRight.Lon-origin.Lon > 180 {
+			// the right edge is nearer going around the antimeridian
+			d = math.Min(d, haversineDistance(origin, nearestRightEdge(origin, rec)))
+		}
+		return d
 	}
 
 	if origin.Lon > rec.TopRight.Lon {
-		return haversineDistance(origin, nearestRightEdge(origin, rec))
+		d := haversineDistance(origin, nearestRightEdge(origin, rec))
+		if origin.Lon-rec.TopLeft.Lon > 180 {
+			// the left edge is nearer going around the antimeridian
+			d = math.Min(d, haversineDistance(origin, nearestLeftEdge(origin, rec)))
+		}
+		return d
 	}
 
 	minDistance := math.MaxFloat64
@@ -220,24 +652,45 @@ func minDistanceToGeohash(origin Pos, hash Hash) float64 {
 }
 
 func (h Hash) addOffset(offset posOffset) Hash {
-	bitCount := h.precision * 5
-	latPrecision := bitCount >> 1
-	lonPrecision := bitCount - latPrecision
+	latPrecision, lonPrecision := bitPrecisions(h.bits)
 
-	latMask := uint32((1 << latPrecision) - 1)
-	lonMask := uint32((1 << lonPrecision) - 1)
+	latMask := uint64(1)<<latPrecision - 1
+	lonMask := uint64(1)<<lonPrecision - 1
 
-	h.lat = (h.lat + uint32(int(latMask)+1+offset.lat)) & latMask
-	h.lon = (h.lon + uint32(int(lonMask)+1+offset.lon)) & lonMask
+	// adding a negative offset wraps around, the same as subtracting
+	h.lat = (h.lat + uint64(offset.lat)) & latMask
+	h.lon = (h.lon + uint64(offset.lon)) & lonMask
 
 	return h
 }
 
-// NearbyGeohashList computes nearby geohashes, radius is in km
+// NearbyGeohashList computes nearby geohashes, radius is in km.
+// The search stops at the poles and wraps around the antimeridian, each geohash is returned at most once
 func NearbyGeohashList(origin Pos, radius float64, precision uint32) []Hash {
+	var result []Hash
+	ForEachNearby(origin, radius, precision, func(h Hash, minDistance float64) bool {
+		result = append(result, h)
+		return true
+	})
+	return result
+}
+
+// ForEachNearby calls fn for each geohash whose minimum distance to the origin is not greater than radius,
+// in the same order as NearbyGeohashList: ring by ring, starting from the geohash containing the origin.
+// When the circle covers all longitudes (e.g. it contains a pole), the other geohashes are listed
+// row by row from the bottom instead, each row outward from the column of the origin. Radius and minDistance are in km. The iteration stops when fn returns false
+func ForEachNearby(origin Pos, radius float64, precision uint32, fn func(h Hash, minDistance float64) bool) {
 	h := ComputeGeohash(origin, precision)
+	if !fn(h, 0) {
+		return
+	}
 
-	result := []Hash{h}
+	bounds := Circle{Center: origin, Radius: radius}.RectBound()
+	if bounds.Min.Lon == -180 && bounds.Max.Lon == 180 {
+		// the rings would grow around the whole longitude range
+		forEachNearbyByRows(origin, radius, precision, bounds, fn)
+		return
+	}
 
 	for distance := 1; ; distance++ {
 		continuing := false
@@ -245,7 +698,10 @@ func NearbyGeohashList(origin Pos, radius float64, precision uint32) []Hash {
 		offset := posOffset{lat: 0, lon: distance}
 		ok := true
 		for ; ok; offset, ok = nearbyNext(offset, distance) {
-			newHash := h.addOffset(offset)
+			newHash, inside := h.ringOffset(offset)
+			if !inside {
+				continue
+			}
 
 			d := minDistanceToGeohash(origin, newHash)
 			if d > radius {
@@ -253,15 +709,125 @@ func NearbyGeohashList(origin Pos, radius float64, precision uint32) []Hash {
 			}
 
 			continuing = true
-			result = append(result, newHash)
+			if !fn(newHash, d) {
+				return
+			}
 		}
 
 		if !continuing {
-			return result
+			return
+		}
+	}
+}
+
+// forEachNearbyByRows walks the rows of the bounds from the bottom, skipping the geohash containing the origin.
+// In each row, the columns are walked outward from the column of the origin, to the right then to the left,
+// until their min distances are greater than radius, because the min distances of the geohashes of a row
+// do not decrease when moving away from the column of the origin
+func forEachNearbyByRows(origin Pos, radius float64, precision uint32, bounds Bounds, fn func(h Hash, d float64) bool) {
+	start, latSteps, _, ok := rectangleSteps(bounds, precision)
+	if !ok {
+		return
+	}
+
+	h := ComputeGeohash(origin, precision)
+	_, lonPrecision := bitPrecisions(h.bits)
+	lonCount := 1 << lonPrecision
+
+	for row := 0; row <= latSteps; row++ {
+		latOffset := int(start.lat) + row - int(h.lat)
+
+		// the same window of the longitude offsets as ringOffset, so each column is visited once
+		if !forEachNearbyInRow(origin, radius, h, latOffset, 0, lonCount/2, 1, fn) {
+			return
+		}
+		if !forEachNearbyInRow(origin, radius, h, latOffset, 1, (lonCount-1)/2, -1, fn) {
+			return
 		}
 	}
 }
 
+// forEachNearbyInRow walks the columns of the row at the distances [from, to] from the column of the origin
+// in the direction of step, until a geohash is farther than radius. Returns false if fn returns false
+func forEachNearbyInRow(origin Pos, radius float64, h Hash, latOffset, from, to, step int, fn func(h Hash, d float64) bool) bool {
+	for column := from; column <= to; column++ {
+		cell := h.addOffset(posOffset{lat: latOffset, lon: column * step})
+
+		d := minDistanceToGeohash(origin, cell)
+		if d > radius {
+			return true
+		}
+		if cell == h {
+			continue
+		}
+		if !fn(cell, d) {
+			return false
+		}
+	}
+	return true
+}
+
+// NearbyHash is a geohash annotated with its distances to the origin of the search, in km
+type NearbyHash struct {
+	Hash        Hash
+	MinDistance float64 // the distance to the nearest point of the geohash
+	MaxDistance float64 // the distance to the farthest point of the geohash
+}
+
+// NearbyGeohashListByDistance is the same as NearbyGeohashList but the geohashes are annotated
+// with their min and max distances and sorted by the min distance, ties are kept in the ring order.
+// A search for k nearest points can stop probing once it has k points closer than the next MinDistance
+func NearbyGeohashListByDistance(origin Pos, radius float64, precision uint32) []NearbyHash {
+	var result []NearbyHash
+	ForEachNearby(origin, radius, precision, func(h Hash, minDistance float64) bool {
+		result = append(result, NearbyHash{
+			Hash:        h,
+			MinDistance: minDistance,
+			MaxDistance: maxDistanceToGeohash(origin, h),
+		})
+		return true
+	})
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].MinDistance < result[j].MinDistance
+	})
+	return result
+}
+
+// DefaultNearbyMaxCells is the number of cells used by ChoosePrecision when maxCells <= 0,
+// a 3x3 block of cells, each cell is at least as large as the radius
+const DefaultNearbyMaxCells = 9
+
+// ChoosePrecision returns the finest precision such that the cells intersecting the bounding box
+// of the circle, computed with the actual cell dimensions at its latitude, are not more than maxCells.
+// Radius is in km, maxCells <= 0 means DefaultNearbyMaxCells. Returns 1 if no precision satisfies maxCells
+func ChoosePrecision(origin Pos, radius float64, maxCells int) uint32 {
+	if maxCells <= 0 {
+		maxCells = DefaultNearbyMaxCells
+	}
+
+	bounds := Circle{Center: origin, Radius: radius}.RectBound()
+
+	result := uint32(1)
+	for precision := uint32(2); precision <= MaxPrecision; precision++ {
+		_, latSteps, lonSteps, _ := rectangleSteps(bounds, precision)
+
+		rows := uint64(latSteps) + 1
+		columns := uint64(lonSteps) + 1
+		if rows > uint64(maxCells)/columns {
+			break
+		}
+		result = precision
+	}
+	return result
+}
+
+// NearbyGeohashListAuto is NearbyGeohashList with the precision computed by ChoosePrecision
+// with DefaultNearbyMaxCells, radius is in km
+func NearbyGeohashListAuto(origin Pos, radius float64) []Hash {
+	return NearbyGeohashList(origin, radius, ChoosePrecision(origin, radius, 0))
+}
+
 type posOffset struct {
 	lat int
 	lon int
@@ -337,10 +903,21 @@ func nearestBottomEdge(pos Pos, rec Rectangle) Pos {
 }
 
 func nearestVerticalEdge(pos Pos, lon float64, rec Rectangle) Pos {
-	lat := haversine.MinLatDistance(pos.toHaversine(), lon)
-
 	minLat := rec.BottomLeft.Lat
 	maxLat := rec.TopLeft.Lat
+
+	if math.Cos((lon-pos.Lon)*math.Pi/180) < 0 {
+		// more than 90 degrees away, the distance along the meridian has its maximum
+		// instead of its minimum at MinLatDistance, so the nearest point is one of the ends
+		bottom := Pos{Lat: minLat, Lon: lon}
+		top := Pos{Lat: maxLat, Lon: lon}
+		if haversineDistance(pos, bottom) < haversineDistance(pos, top) {
+			return bottom
+		}
+		return top
+	}
+
+	lat := haversine.MinLatDistance(pos.toHaversine(), lon)
 	if lat < minLat {
 		lat = minLat
 	} else if lat > maxLat {
@@ -3,6 +3,7 @@ package geohash
 import (
 	"fmt"
 	"github.com/stretchr/testify/assert"
+	"math"
 	"math/rand"
 	"testing"
 	"time"
@@ -25,6 +26,23 @@ func TestSpacing(t *testing.T) {
 	assert.Equal(t, uint64(0b101010101000001), result)
 }
 
+func TestUnspacing(t *testing.T) {
+	result := unspacing(0b101, 2)
+	assert.Equal(t, uint64(0b11), result)
+
+	result = unspacing(0b10001, 3)
+	assert.Equal(t, uint64(0b101), result)
+
+	result = unspacing(0b1010101000001, 7)
+	assert.Equal(t, uint64(0b1111001), result)
+
+	result = unspacing(0b111010101000001, 8)
+	assert.Equal(t, uint64(0b11111001), result)
+
+	result = unspacing(spacing(0xffffffff, 32), 32)
+	assert.Equal(t, uint64(0xffffffff), result)
+}
+
 func TestComputeGeohash(t *testing.T) {
 	result := ComputeGeohash(Pos{
 		Lat: 48.669,
@@ -111,6 +129,308 @@ func TestComputeGeohash_Case4(t *testing.T) {
 	assert.Equal(t, "6uzvrn8", result)
 }
 
+func TestComputeGeohash_High_Precision(t *testing.T) {
+	result := ComputeGeohash(Pos{
+		Lat: 48.66746123456,
+		Lon: 22.44043987654,
+	}, 20).String()
+	assert.Equal(t, "u2xuyesss0kf2j7jhn6z", result)
+
+	result = ComputeGeohash(Pos{
+		Lat: -33.8688197,
+		Lon: 151.2092955,
+	}, 16).String()
+	assert.Equal(t, "r3gx2f75zfqszujn", result)
+
+	h := ComputeGeohash(Pos{
+		Lat: 48.66746123456,
+		Lon: 22.44043987654,
+	}, 13)
+	assert.Equal(t, "u2xuyesss0kf2", h.String())
+	assert.Equal(t, "u2xuyesss0kdr", h.Left().String())
+	assert.Equal(t, "u2xuyesss0kf3", h.Right().String())
+	assert.Equal(t, "u2xuyesss0kf8", h.Top().String())
+	assert.Equal(t, "u2xuyesss0kf0", h.Bottom().String())
+
+	const latSize = 180.0 / (1 << 32)
+	const lonSize = 360.0 / (1 << 33)
+
+	rec := h.Rec()
+	assert.InDelta(t, 48.66746123456, rec.BottomLeft.Lat, latSize)
+	assert.InDelta(t, 22.44043987654, rec.BottomLeft.Lon, lonSize)
+	assert.Equal(t, latSize, rec.TopLeft.Lat-rec.BottomLeft.Lat)
+	assert.Equal(t, lonSize, rec.BottomRight.Lon-rec.BottomLeft.Lon)
+	assert.Equal(t, h.Pos(), rec.BottomLeft)
+}
+
+func TestComputeGeohashBits(t *testing.T) {
+	pos := Pos{
+		Lat: 48.669,
+		Lon: 22.445,
+	}
+
+	h := ComputeGeohashBits(pos, 25)
+	assert.Equal(t, ComputeGeohash(pos, 5), h)
+	assert.Equal(t, uint32(25), h.BitPrecision())
+	assert.Equal(t, "u2xuy", h.String())
+
+	h = ComputeGeohashBits(pos, 26)
+	assert.Equal(t, uint32(26), h.BitPrecision())
+	assert.Equal(t, "", h.String())
+
+	const latSize = 180.0 / (1 << 13)
+	const lonSize = 360.0 / (1 << 13)
+
+	rec := h.Rec()
+	assert.InDelta(t, pos.Lat, rec.BottomLeft.Lat, latSize)
+	assert.InDelta(t, pos.Lon, rec.BottomLeft.Lon, lonSize)
+	assert.Equal(t, latSize, rec.TopLeft.Lat-rec.BottomLeft.Lat)
+	assert.Equal(t, lonSize, rec.BottomRight.Lon-rec.BottomLeft.Lon)
+	assert.Equal(t, ComputeGeohash(pos, 5).Pos().Lat, rec.BottomLeft.Lat)
+
+	assert.Equal(t, h, h.Left().Right())
+	assert.Equal(t, h, h.Top().Bottom())
+	assert.Equal(t, rec.TopLeft, h.Top().Pos())
+	assert.Equal(t, rec.BottomRight, h.Right().Pos())
+	assert.Equal(t, Pos{Lat: rec.BottomLeft.Lat, Lon: rec.BottomLeft.Lon - lonSize}, h.Left().Pos())
+	assert.Equal(t, Pos{Lat: rec.BottomLeft.Lat - latSize, Lon: rec.BottomLeft.Lon}, h.Bottom().Pos())
+}
+
+func TestComputeGeohashBits_Low_Precision(t *testing.T) {
+	h := ComputeGeohashBits(Pos{
+		Lat: -10,
+		Lon: -100,
+	}, 3)
+	assert.Equal(t, uint32(3), h.BitPrecision())
+	assert.Equal(t, Rectangle{
+		BottomLeft:  Pos{Lat: -90, Lon: -180},
+		BottomRight: Pos{Lat: -90, Lon: -90},
+		TopLeft:     Pos{Lat: 0, Lon: -180},
+		TopRight:    Pos{Lat: 0, Lon: -90},
+	}, h.Rec())
+
+	assert.Equal(t, Pos{Lat: -90, Lon: 90}, h.Left().Pos())
+	assert.Equal(t, Pos{Lat: -90, Lon: -90}, h.Right().Pos())
+}
+
+func TestHash_Uint64(t *testing.T) {
+	h, err := Parse("u2xuyess")
+	assert.Equal(t, nil, err)
+
+	var expected uint64
+	for _, c := range []uint64{26, 2, 29, 26, 30, 13, 24, 24} {
+		expected = expected<<5 | c
+	}
+	assert.Equal(t, expected, mustUint64(h))
+
+	h = ComputeGeohashBits(Pos{Lat: 0, Lon: 0}, 1)
+	assert.Equal(t, uint64(1), mustUint64(h))
+
+	h = ComputeGeohashBits(Pos{Lat: 90, Lon: 180}, 64)
+	assert.Equal(t, uint64(math.MaxUint64), mustUint64(h))
+
+	h, err = Parse("u2xuyesss0kf2j7jhn6z")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, ComputeGeohashBits(h.Pos(), 64), h.truncate(64))
+
+	value, err := h.Uint64()
+	assert.Equal(t, ErrInvalidPrecision, err)
+	assert.Equal(t, uint64(0), value)
+}
+
+func TestFromUint64(t *testing.T) {
+	h, err := FromUint64(0b11010_00010_11101, 15)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "u2x", h.String())
+
+	h, err = FromUint64(math.MaxUint64, 64)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, ComputeGeohashBits(Pos{Lat: 90, Lon: 180}, 64), h)
+
+	_, err = FromUint64(0, 0)
+	assert.Equal(t, ErrInvalidPrecision, err)
+
+	_, err = FromUint64(0, 65)
+	assert.Equal(t, ErrInvalidPrecision, err)
+
+	_, err = FromUint64(0b1000, 3)
+	assert.Equal(t, ErrValueOutOfRange, err)
+}
+
+func TestComputeGeohash_Boundaries(t *testing.T) {
+	h := ComputeGeohash(Pos{
+		Lat: 90,
+		Lon: 180,
+	}, 5)
+	assert.Equal(t, "zzzzz", h.String())
+
+	h = ComputeGeohash(Pos{
+		Lat: -90,
+		Lon: -180,
+	}, 5)
+	assert.Equal(t, "00000", h.String())
+
+	h = ComputeGeohash(Pos{
+		Lat: 90,
+		Lon: 0,
+	}, 12)
+	assert.Equal(t, "upbpbpbpbpbp", h.String())
+}
+
+func TestComputeGeohashChecked(t *testing.T) {
+	h, err := ComputeGeohashChecked(Pos{
+		Lat: 48.669,
+		Lon: 22.445,
+	}, 4)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "u2xu", h.String())
+
+	h, err = ComputeGeohashChecked(Pos{
+		Lat: 90,
+		Lon: 180,
+	}, 12)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "zzzzzzzzzzzz", h.String())
+
+	tests := []struct {
+		name      string
+		pos       Pos
+		precision uint32
+		err       error
+	}{
+		{name: "zero-precision", pos: Pos{}, precision: 0, err: ErrInvalidPrecision},
+		{name: "too-large-precision", pos: Pos{}, precision: MaxPrecision + 1, err: ErrInvalidPrecision},
+		{name: "lat-too-large", pos: Pos{Lat: 90.1}, precision: 5, err: ErrInvalidLatitude},
+		{name: "lat-too-small", pos: Pos{Lat: -90.1}, precision: 5, err: ErrInvalidLatitude},
+		{name: "lat-nan", pos: Pos{Lat: math.NaN()}, precision: 5, err: ErrInvalidLatitude},
+		{name: "lon-too-large", pos: Pos{Lon: 180.1}, precision: 5, err: ErrInvalidLongitude},
+		{name: "lon-too-small", pos: Pos{Lon: -180.1}, precision: 5, err: ErrInvalidLongitude},
+		{name: "lon-nan", pos: Pos{Lon: math.NaN()}, precision: 5, err: ErrInvalidLongitude},
+		{name: "lon-inf", pos: Pos{Lon: math.Inf(1)}, precision: 5, err: ErrInvalidLongitude},
+	}
+	for _, e := range tests {
+		tc := e
+		t.Run(tc.name, func(t *testing.T) {
+			h, err := ComputeGeohashChecked(tc.pos, tc.precision)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, Hash{}, h)
+		})
+	}
+}
+
+func TestComputeGeohashBitsChecked(t *testing.T) {
+	pos := Pos{Lat: 48.669, Lon: 22.445}
+
+	h, err := ComputeGeohashBitsChecked(pos, 26)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, ComputeGeohashBits(pos, 26), h)
+
+	h, err = ComputeGeohashBitsChecked(pos, MaxBitPrecision)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, ComputeGeohash(pos, MaxPrecision), h)
+
+	tests := []struct {
+		name         string
+		pos          Pos
+		bitPrecision uint32
+		err          error
+	}{
+		{name: "zero-precision", pos: Pos{}, bitPrecision: 0, err: ErrInvalidPrecision},
+		{name: "too-large-precision", pos: Pos{}, bitPrecision: MaxBitPrecision + 1, err: ErrInvalidPrecision},
+		{name: "overflow-precision", pos: Pos{}, bitPrecision: 130, err: ErrInvalidPrecision},
+		{name: "lat-too-large", pos: Pos{Lat: 90.1}, bitPrecision: 26, err: ErrInvalidLatitude},
+		{name: "lat-nan", pos: Pos{Lat: math.NaN()}, bitPrecision: 26, err: ErrInvalidLatitude},
+		{name: "lon-too-small", pos: Pos{Lon: -180.1}, bitPrecision: 26, err: ErrInvalidLongitude},
+	}
+	for _, e := range tests {
+		tc := e
+		t.Run(tc.name, func(t *testing.T) {
+			h, err := ComputeGeohashBitsChecked(tc.pos, tc.bitPrecision)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, Hash{}, h)
+		})
+	}
+}
+
+func TestHash_Parent(t *testing.T) {
+	h := mustParse("w3gv2")
+	assert.Equal(t, mustParse("w3gv"), h.Parent())
+	assert.Equal(t, mustParse("w"), h.Parent().Parent().Parent().Parent())
+	assert.Equal(t, Hash{}, mustParse("w").Parent())
+	assert.Equal(t, Hash{}, Hash{}.Parent())
+
+	// truncated to the previous multiple of 5 bits
+	h = ComputeGeohashBits(Pos{Lat: 10.7769, Lon: 106.7009}, 13)
+	assert.Equal(t, mustParse("w3"), h.Parent())
+	h = ComputeGeohashBits(Pos{Lat: 10.7769, Lon: 106.7009}, 3)
+	assert.Equal(t, Hash{}, h.Parent())
+}
+
+func TestHash_Children(t *testing.T) {
+	h := mustParse("w3")
+	children := h.Children()
+	assert.Equal(t, 32, len(children))
+	for i, child := range children {
+		assert.Equal(t, "w3"+string(encoding[i]), child.String())
+		assert.Equal(t, h, child.Parent())
+	}
+
+	assert.Equal(t, 32, len(Hash{}.Children()))
+	assert.Equal(t, "0", Hash{}.Children()[0].String())
+
+	h = ComputeGeohash(Pos{Lat: 10.7769, Lon: 106.7009}, MaxPrecision)
+	assert.Equal(t, []Hash(nil), h.Children())
+}
+
+func TestHash_ChildrenAt(t *testing.T) {
+	h := mustParse("w3")
+	assert.Equal(t, []Hash{h}, h.ChildrenAt(2))
+	assert.Equal(t, h.Children(), h.ChildrenAt(3))
+	assert.Equal(t, []Hash(nil), h.ChildrenAt(1))
+	assert.Equal(t, []Hash(nil), h.ChildrenAt(MaxPrecision+1))
+
+	// too many sub cells
+	assert.Equal(t, []Hash(nil), mustParse("u").ChildrenAt(20))
+	assert.Equal(t, []Hash(nil), mustParse("u").ChildrenAt(1+MaxChildrenDepth+1))
+	assert.Equal(t, 1<<(5*MaxChildrenDepth), len(mustParse("u").ChildrenAt(1+MaxChildrenDepth)))
+
+	children := h.ChildrenAt(4)
+	assert.Equal(t, 32*32, len(children))
+	assert.Equal(t, "w300", children[0].String())
+	assert.Equal(t, "w30z", children[31].String())
+	assert.Equal(t, "w310", children[32].String())
+	assert.Equal(t, "w3zz", children[len(children)-1].String())
+
+	// from a number of bits that is not a multiple of 5
+	h = ComputeGeohashBits(Pos{Lat: 10.7769, Lon: 106.7009}, 8)
+	children = h.ChildrenAt(2)
+	assert.Equal(t, 4, len(children))
+	for _, child := range children {
+		assert.Equal(t, true, h.IsAncestorOf(child))
+	}
+}
+
+func TestHash_Contains(t *testing.T) {
+	h := mustParse("w3gv")
+	assert.Equal(t, true, h.Contains(h))
+	assert.Equal(t, true, h.Contains(mustParse("w3gv2")))
+	assert.Equal(t, true, h.Contains(mustParse("w3gv2xyz")))
+	assert.Equal(t, false, h.Contains(mustParse("w3gu2")))
+	assert.Equal(t, false, h.Contains(mustParse("w3g")))
+	assert.Equal(t, true, Hash{}.Contains(h))
+
+	assert.Equal(t, false, h.IsAncestorOf(h))
+	assert.Equal(t, true, h.IsAncestorOf(mustParse("w3gv2")))
+	assert.Equal(t, true, mustParse("w").IsAncestorOf(h))
+	assert.Equal(t, false, mustParse("w3gv2").IsAncestorOf(h))
+}
+
+func TestHash_Precision(t *testing.T) {
+	assert.Equal(t, uint32(4), mustParse("w3gv").Precision())
+	assert.Equal(t, uint32(0), Hash{}.Precision())
+	assert.Equal(t, uint32(2), ComputeGeohashBits(Pos{}, 13).Precision())
+}
+
 func TestGeohash_Left_And_Right(t *testing.T) {
 	h := ComputeGeohash(Pos{
 		Lat: -17.3218,
@@ -167,7 +487,8 @@ func TestGeohash_Top_And_Bot(t *testing.T) {
 		Lon: -179.97802734,
 	}, 5)
 	assert.Equal(t, "00002", h.Top().String())
-	assert.Equal(t, "bpbpb", h.Bottom().String())
+	// across the south pole
+	assert.Equal(t, "h0000", h.Bottom().String())
 }
 
 func TestGeohash_Top_And_Bottom_Case_2(t *testing.T) {
@@ -176,7 +497,8 @@ func TestGeohash_Top_And_Bottom_Case_2(t *testing.T) {
 		Lon: 179.97802734,
 	}, 5)
 	assert.Equal(t, "zzzzz", h.String())
-	assert.Equal(t, "pbpbp", h.Top().String())
+	// across the north pole
+	assert.Equal(t, "gzzzz", h.Top().String())
 	assert.Equal(t, "zzzzx", h.Bottom().String())
 }
 
@@ -206,6 +528,130 @@ func TestGeohash_Rec(t *testing.T) {
 	}, h.Rec())
 }
 
+func TestGeohash_Rec_Last_Column(t *testing.T) {
+	assert.Equal(t, Rectangle{
+		BottomLeft:  Pos{Lat: 84.375, Lon: 168.75},
+		BottomRight: Pos{Lat: 84.375, Lon: 180},
+		TopLeft:     Pos{Lat: 90, Lon: 168.75},
+		TopRight:    Pos{Lat: 90, Lon: 180},
+	}, mustParse("zz").Rec())
+}
+
+func TestHash_Bounds_And_Center(t *testing.T) {
+	h := mustParse("zz")
+	assert.Equal(t, Bounds{
+		Min: Pos{Lat: 84.375, Lon: 168.75},
+		Max: Pos{Lat: 90, Lon: 180},
+	}, h.Bounds())
+	assert.Equal(t, Pos{Lat: 87.1875, Lon: 174.375}, h.Center())
+
+	h = mustParse("s0000")
+	assert.Equal(t, Pos{Lat: 0.02197265625, Lon: 0.02197265625}, h.Center())
+	assert.Equal(t, h, ComputeGeohash(h.Center(), 5))
+}
+
+func TestHash_Dimensions(t *testing.T) {
+	h := mustParse("s0000")
+	assert.InDelta(t, 4.8865, h.WidthKm(), 1e-4)
+	assert.InDelta(t, 4.8865, h.HeightKm(), 1e-4)
+	assert.InDelta(t, 23.8779, h.AreaKm2(), 1e-4)
+
+	// the same dimensions at the north and the south poles
+	north := mustParse("zz")
+	south := mustParse("pb")
+	assert.InDelta(t, 61.2826, north.WidthKm(), 1e-4)
+	assert.InDelta(t, 625.4723, north.HeightKm(), 1e-4)
+	assert.InDelta(t, 38376.6685, north.AreaKm2(), 1e-4)
+	assert.InDelta(t, north.WidthKm(), south.WidthKm(), 1e-9)
+	assert.InDelta(t, north.HeightKm(), south.HeightKm(), 1e-9)
+	assert.InDelta(t, north.AreaKm2(), south.AreaKm2(), 1e-9)
+
+	// the last column at the antimeridian
+	h = mustParse("zzzzz")
+	assert.InDelta(t, 0.00187, h.WidthKm(), 1e-5)
+	assert.InDelta(t, 4.8865, h.HeightKm(), 1e-4)
+
+	sum := 0.0
+	for _, child := range (Hash{}).Children() {
+		sum += child.AreaKm2()
+	}
+	assert.InDelta(t, 4*math.Pi*earthRadius*earthRadius, sum, 1e-3)
+}
+
+func TestGeohash_Left_And_Right_Antimeridian(t *testing.T) {
+	h := mustParse("zzzzz")
+	assert.Equal(t, "bpbpb", h.Right().String())
+	assert.Equal(t, h, h.Right().Left())
+
+	h = mustParse("xcz")
+	assert.Equal(t, "81b", h.Right().String())
+	assert.Equal(t, h, h.Right().Left())
+}
+
+func TestGeohash_Offset(t *testing.T) {
+	h := mustParse("s0000")
+
+	result, ok := h.Offset(1, 1)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, "s0003", result.String())
+
+	result, ok = h.Offset(0, -1)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, h.Left(), result)
+
+	h = mustParse("zzzzz")
+	_, ok = h.Offset(1, 0)
+	assert.Equal(t, false, ok)
+
+	result, ok = h.Offset(-1, 1)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, "bpbp8", result.String())
+
+	h = mustParse("00000")
+	_, ok = h.Offset(-1, 0)
+	assert.Equal(t, false, ok)
+
+	_, ok = h.Offset(-1000, 0)
+	assert.Equal(t, false, ok)
+}
+
+func TestParse(t *testing.T) {
+	h, err := Parse("u2xuyess")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, ComputeGeohash(Pos{
+		Lat: 48.66746,
+		Lon: 22.44043,
+	}, 8), h)
+	assert.Equal(t, "u2xuyess", h.String())
+
+	h, err = Parse("kq0g71w")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, ComputeGeohash(Pos{
+		Lat: -10.6698,
+		Lon: 12.4457,
+	}, 7), h)
+
+	h, err = Parse("s0000")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Pos{Lat: 0, Lon: 0}, h.Pos())
+}
+
+func TestParse_Errors(t *testing.T) {
+	_, err := Parse("")
+	assert.Equal(t, ErrEmptyGeohash, err)
+
+	_, err = Parse("u2xuyessu2xuyessu2xuy")
+	assert.Equal(t, ErrGeohashTooLong, err)
+
+	for _, c := range []byte{'a', 'i', 'l', 'o', 'A', '-'} {
+		_, err = Parse("u2x" + string(c))
+		assert.Equal(t, &InvalidCharacterError{Char: c, Index: 3}, err)
+	}
+
+	_, err = Parse("i2xu")
+	assert.Equal(t, "geohash: invalid character 'i' at index 0", err.Error())
+}
+
 func TestNearbyNext(t *testing.T) {
 	offset := posOffset{
 		lat: 0,
@@ -348,6 +794,213 @@ func TestNearbyGeohashList(t *testing.T) {
 	}, hashList)
 }
 
+func TestForEachNearby(t *testing.T) {
+	origin := Pos{
+		Lat: 0.7,
+		Lon: 0.7,
+	}
+	h := ComputeGeohash(origin, 3)
+
+	var hashes []Hash
+	var distances []float64
+	ForEachNearby(origin, 80, 3, func(h Hash, minDistance float64) bool {
+		hashes = append(hashes, h)
+		distances = append(distances, minDistance)
+		return true
+	})
+	assert.Equal(t, NearbyGeohashList(origin, 80, 3), hashes)
+	assert.Equal(t, 0.0, distances[0])
+	for i := range hashes {
+		assert.Equal(t, minDistanceToGeohash(origin, hashes[i]), distances[i])
+		assert.LessOrEqual(t, distances[i], 80.0)
+	}
+
+	// stop early
+	hashes = nil
+	ForEachNearby(origin, 120, 3, func(h Hash, minDistance float64) bool {
+		hashes = append(hashes, h)
+		return len(hashes) < 3
+	})
+	assert.Equal(t, []Hash{h, h.Right(), h.Right().Top()}, hashes)
+
+	hashes = nil
+	ForEachNearby(origin, 120, 3, func(h Hash, minDistance float64) bool {
+		hashes = append(hashes, h)
+		return false
+	})
+	assert.Equal(t, []Hash{h}, hashes)
+}
+
+func TestNearbyGeohashListByDistance(t *testing.T) {
+	origin := Pos{
+		Lat: 0.7,
+		Lon: 0.7,
+	}
+	h := ComputeGeohash(origin, 3)
+
+	result := NearbyGeohashListByDistance(origin, 120, 3)
+
+	var hashes []Hash
+	for _, n := range result {
+		hashes = append(hashes, n.Hash)
+		assert.Equal(t, minDistanceToGeohash(origin, n.Hash), n.MinDistance)
+		assert.Equal(t, maxDistanceToGeohash(origin, n.Hash), n.MaxDistance)
+		assert.Less(t, n.MinDistance, n.MaxDistance)
+	}
+	assert.Equal(t, []Hash{
+		h,
+		h.Left(), h.Bottom(), h.Right(), h.Top(),
+		h.Bottom().Left(), h.Top().Left(), h.Bottom().Right(), h.Right().Top(),
+	}, hashes)
+	assert.Equal(t, hashListToStrings(NearbyGeohashList(origin, 120, 3)), hashListToStrings(hashes))
+
+	assert.Equal(t, 0.0, result[0].MinDistance)
+	assert.InDelta(t, 111.0506, result[0].MaxDistance, 1e-4)
+
+	for i := 1; i < len(result); i++ {
+		assert.LessOrEqual(t, result[i-1].MinDistance, result[i].MinDistance)
+	}
+}
+
+func TestChoosePrecision(t *testing.T) {
+	origin := Pos{
+		Lat: 0.7,
+		Lon: 0.7,
+	}
+
+	// the cells of precision 4 are about 39km x 19.5km, 4 rows x 2 columns
+	assert.Equal(t, uint32(4), ChoosePrecision(origin, 20, 0))
+	assert.Equal(t, uint32(4), ChoosePrecision(origin, 20, 8))
+	assert.Equal(t, uint32(3), ChoosePrecision(origin, 20, 7))
+	assert.Equal(t, uint32(3), ChoosePrecision(origin, 80, 0))
+	assert.Equal(t, uint32(5), ChoosePrecision(origin, 20, 100))
+	assert.Equal(t, uint32(1), ChoosePrecision(origin, 20000, 0))
+
+	// the cells are narrower at high latitudes
+	assert.Equal(t, uint32(7), ChoosePrecision(Pos{Lat: 0.2, Lon: 0.2}, 0.2, 0))
+	assert.Equal(t, uint32(6), ChoosePrecision(Pos{Lat: 70.2, Lon: 0.2}, 0.2, 0))
+}
+
+func TestNearbyGeohashList_Poles(t *testing.T) {
+	origin := Pos{Lat: 89.9, Lon: 10}
+	hashes := NearbyGeohashList(origin, 600, 2)
+
+	// the whole top row, without cells from the south pole
+	set := hashListToStrings(hashes)
+	assert.Equal(t, 32, len(hashes))
+	assert.Equal(t, 32, len(set))
+	for _, h := range hashes {
+		assert.Equal(t, 90.0, h.Rec().TopLeft.Lat)
+	}
+
+	origin = Pos{Lat: -89.9, Lon: -170}
+	hashes = NearbyGeohashList(origin, 10, 3)
+	for _, h := range hashes {
+		assert.Equal(t, -90.0, h.Pos().Lat)
+	}
+	assert.Equal(t, len(hashes), len(hashListToStrings(hashes)))
+}
+
+func TestNearbyGeohashList_Containing_The_Pole(t *testing.T) {
+	origin := Pos{Lat: 89.99, Lon: 10}
+	hashes := NearbyGeohashList(origin, 3, 5)
+
+	assert.Equal(t, ComputeGeohash(origin, 5), hashes[0])
+	assert.Equal(t, len(hashes), len(hashListToStrings(hashes)))
+
+	expected := map[string]struct{}{}
+	for _, h := range CoverRectangle(Bounds{
+		Min: Pos{Lat: 89.9, Lon: -180},
+		Max: Pos{Lat: 90, Lon: 180},
+	}, 5) {
+		if minDistanceToGeohash(origin, h) <= 3 {
+			expected[h.String()] = struct{}{}
+		}
+	}
+	assert.Equal(t, expected, hashListToStrings(hashes))
+}
+
+func TestForEachNearby_Containing_The_Pole_Stops_Early(t *testing.T) {
+	for _, precision := range []uint32{8, 9, 12, MaxPrecision} {
+		count := 0
+		maxDistance := 0.0
+		allocs := testing.AllocsPerRun(5, func() {
+			count = 0
+			ForEachNearby(Pos{Lat: 89.999, Lon: 0}, 0.2, precision, func(h Hash, minDistance float64) bool {
+				maxDistance = math.Max(maxDistance, minDistance)
+				count++
+				return count < 5
+			})
+		})
+		assert.Equal(t, 5, count)
+		assert.LessOrEqual(t, maxDistance, 0.2)
+		assert.Equal(t, 0.0, allocs, precision)
+	}
+}
+
+func TestNearbyGeohashList_Containing_The_Pole_Properties_Based_Testing(t *testing.T) {
+	seed := time.Now().Unix()
+	fmt.Println("SEED:", seed)
+	rand.Seed(seed)
+
+	world := CoverRectangle(Bounds{
+		Min: Pos{Lat: -90, Lon: -180},
+		Max: Pos{Lat: 90, Lon: 180},
+	}, 3)
+
+	for i := 0; i < 20; i++ {
+		origin := Pos{Lat: mathRand(85, 90), Lon: mathRand(-180, 180)}
+		if i%2 == 0 {
+			origin.Lat = -origin.Lat
+		}
+		radius := mathRand(0, 1500)
+
+		hashes := NearbyGeohashList(origin, radius, 3)
+		assert.Equal(t, len(hashes), len(hashListToStrings(hashes)))
+
+		expected := map[string]struct{}{
+			ComputeGeohash(origin, 3).String(): {},
+		}
+		for _, h := range world {
+			if minDistanceToGeohash(origin, h) <= radius {
+				expected[h.String()] = struct{}{}
+			}
+		}
+		assert.Equal(t, expected, hashListToStrings(hashes))
+	}
+}
+
+func TestNearbyGeohashList_No_Duplicates(t *testing.T) {
+	hashes := NearbyGeohashList(Pos{Lat: 0, Lon: 0}, 30000, 1)
+	assert.Equal(t, 32, len(hashes))
+	assert.Equal(t, 32, len(hashListToStrings(hashes)))
+}
+
+func TestNearbyGeohashList_Antimeridian(t *testing.T) {
+	hashes := NearbyGeohashList(Pos{Lat: 10, Lon: 179.99}, 50, 3)
+	assert.Equal(t, []Hash{
+		mustParse("xcz"), mustParse("81b"), mustParse("xcx"), mustParse("818"),
+	}, hashes)
+}
+
+func TestNearbyGeohashListAuto(t *testing.T) {
+	origin := Pos{
+		Lat: 0.7,
+		Lon: 0.7,
+	}
+	assert.Equal(t, NearbyGeohashList(origin, 20, 4), NearbyGeohashListAuto(origin, 20))
+
+	origin = Pos{
+		Lat: 10.7769,
+		Lon: 106.7009,
+	}
+	for _, radius := range []float64{0.1, 1, 5, 30, 100} {
+		hashes := NearbyGeohashListAuto(origin, radius)
+		assert.LessOrEqual(t, len(hashes), DefaultNearbyMaxCells)
+		assert.Greater(t, hashes[0].BitPrecision(), uint32(0))
+	}
+}
+
 func TestNearestTopEdge(t *testing.T) {
 	h := ComputeGeohash(Pos{
 		Lat: 0.7,
@@ -654,6 +1307,65 @@ func TestNearbyGeohashList_Properties_Based_Testing(t *testing.T) {
 	fmt.Println("Extra Ratio:", 1-ratio)
 }
 
+func TestParse_Properties_Based_Testing(t *testing.T) {
+	seed := time.Now().Unix()
+	fmt.Println("SEED:", seed)
+	rand.Seed(seed)
+
+	for i := 0; i < 10000; i++ {
+		origin := Pos{
+			Lat: mathRand(-90, 90),
+			Lon: mathRand(-180, 180),
+		}
+		prec := uint32(randInt(1, MaxPrecision))
+
+		h := ComputeGeohash(origin, prec)
+
+		parsed, err := Parse(h.String())
+		assert.Equal(t, nil, err)
+		assert.Equal(t, h, parsed)
+	}
+
+	for i := 0; i < 10000; i++ {
+		length := randInt(1, MaxPrecision)
+		s := make([]byte, 0, length)
+		for k := 0; k < length; k++ {
+			s = append(s, encoding[rand.Intn(len(encoding))])
+		}
+
+		h, err := Parse(string(s))
+		assert.Equal(t, nil, err)
+		assert.Equal(t, string(s), h.String())
+	}
+}
+
+func TestUint64_Properties_Based_Testing(t *testing.T) {
+	seed := time.Now().Unix()
+	fmt.Println("SEED:", seed)
+	rand.Seed(seed)
+
+	for i := 0; i < 10000; i++ {
+		bits := uint32(randInt(1, 64))
+
+		a := ComputeGeohashBits(Pos{
+			Lat: mathRand(-90, 90),
+			Lon: mathRand(-180, 180),
+		}, bits)
+		b := ComputeGeohashBits(Pos{
+			Lat: mathRand(-90, 90),
+			Lon: mathRand(-180, 180),
+		}, bits)
+
+		decoded, err := FromUint64(mustUint64(a), bits)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, a, decoded)
+
+		if bits%5 == 0 {
+			assert.Equal(t, a.String() < b.String(), mustUint64(a) < mustUint64(b))
+		}
+	}
+}
+
 func assertIsSubset(t *testing.T, a, b map[string]struct{}) {
 	t.Helper()
 	for e := range a {
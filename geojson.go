@@ -0,0 +1,140 @@
+package geohash
+
+import (
+	"math"
+)
+
+// circleVertices is the number of vertices of the polygon approximating the search circle
+const circleVertices = 64
+
+// FeatureCollection is a GeoJSON FeatureCollection, can be encoded by json.Marshal
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+}
+
+// Feature is a GeoJSON Feature
+type Feature struct {
+	Type       string                 `json:"type"`
+	Geometry   Geometry               `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// Geometry is a GeoJSON Point or Polygon, the coordinates are in [lon, lat] order
+type Geometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+// HashGeoJSON renders the geohash as a FeatureCollection of one polygon,
+// with the geohash string as the "geohash" property
+func HashGeoJSON(h Hash) FeatureCollection {
+	return HashListGeoJSON([]Hash{h})
+}
+
+// HashListGeoJSON renders each geohash as a polygon of its Rec(),
+// with the geohash string as the "geohash" property
+func HashListGeoJSON(hashes []Hash) FeatureCollection {
+	features := make([]Feature, 0, len(hashes))
+	for _, h := range hashes {
+		features = append(features, hashFeature(h))
+	}
+	return newFeatureCollection(features)
+}
+
+// NearbyGeoJSON renders the result of NearbyGeohashList with the search parameters:
+// the geohash polygons, the search circle as a polygon with the "radiusKm" property
+// and the origin as a point. The longitudes of the circle are continuous around the origin,
+// so they may be outside of [-180, 180] near the antimeridian
+func NearbyGeoJSON(origin Pos, radius float64, hashes []Hash) FeatureCollection {
+	features := make([]Feature, 0, len(hashes)+2)
+	for _, h := range hashes {
+		features = append(features, hashFeature(h))
+	}
+
+	features = append(features, Feature{
+		Type: "Feature",
+		Geometry: Geometry{
+			Type:        "Polygon",
+			Coordinates: [][][2]float64{circleRing(origin, radius)},
+		},
+		Properties: map[string]interface{}{
+			"radiusKm": radius,
+		},
+	})
+
+	features = append(features, Feature{
+		Type: "Feature",
+		Geometry: Geometry{
+			Type:        "Point",
+			Coordinates: posCoordinates(origin),
+		},
+		Properties: map[string]interface{}{
+			"origin": true,
+		},
+	})
+
+	return newFeatureCollection(features)
+}
+
+func newFeatureCollection(features []Feature) FeatureCollection {
+	return FeatureCollection{
+		Type:     "FeatureCollection",
+		Features: features,
+	}
+}
+
+func hashFeature(h Hash) Feature {
+	rec := h.Rec()
+	return Feature{
+		Type: "Feature",
+		Geometry: Geometry{
+			Type:        "Polygon",
+			Coordinates: [][][2]float64{rectangleRing(rec)},
+		},
+		Properties: map[string]interface{}{
+			"geohash": h.String(),
+		},
+	}
+}
+
+func posCoordinates(p Pos) [2]float64 {
+	return [2]float64{p.Lon, p.Lat}
+}
+
+// rectangleRing returns the closed counterclockwise ring of the rectangle
+func rectangleRing(rec Rectangle) [][2]float64 {
+	corners := rectangleCorners(rec)
+
+	ring := make([][2]float64, 0, len(corners))
+	for _, p := range corners {
+		ring = append(ring, posCoordinates(p))
+	}
+	return ring
+}
+
+// circleRing returns the closed counterclockwise ring of the destination points
+// at the radius from the center, radius is in km
+func circleRing(center Pos, radius float64) [][2]float64 {
+	angle := radius / earthRadius
+	lat := center.Lat * math.Pi / 180
+	lon := center.Lon * math.Pi / 180
+
+	ring := make([][2]float64, 0, circleVertices+1)
+	for i := 0; i < circleVertices; i++ {
+		// bearings are clockwise from the north
+		bearing := -2 * math.Pi * float64(i) / circleVertices
+
+		destLat := math.Asin(math.Sin(lat)*math.Cos(angle) + math.Cos(lat)*math.Sin(angle)*math.Cos(bearing))
+		destLon := lon + math.Atan2(
+			math.Sin(bearing)*math.Sin(angle)*math.Cos(lat),
+			math.Cos(angle)-math.Sin(lat)*math.Sin(destLat),
+		)
+
+		ring = append(ring, posCoordinates(Pos{
+			Lat: destLat * 180 / math.Pi,
+			Lon: destLon * 180 / math.Pi,
+		}))
+	}
+	return append(ring, ring[0])
+}
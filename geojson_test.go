@@ -0,0 +1,62 @@
+package geohash
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestHashGeoJSON(t *testing.T) {
+	data, err := json.Marshal(HashGeoJSON(mustParse("s")))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"type":"FeatureCollection","features":[{"type":"Feature",`+
+		`"geometry":{"type":"Polygon","coordinates":[[[0,0],[45,0],[45,45],[0,45],[0,0]]]},`+
+		`"properties":{"geohash":"s"}}]}`, string(data))
+
+	// the last column ends at the antimeridian
+	collection := HashGeoJSON(mustParse("zz"))
+	assert.Equal(t, [][][2]float64{{
+		{168.75, 84.375}, {180, 84.375}, {180, 90}, {168.75, 90}, {168.75, 84.375},
+	}}, collection.Features[0].Geometry.Coordinates)
+}
+
+func TestHashListGeoJSON(t *testing.T) {
+	collection := HashListGeoJSON([]Hash{mustParse("s0"), mustParse("u")})
+	assert.Equal(t, "FeatureCollection", collection.Type)
+	assert.Equal(t, 2, len(collection.Features))
+	assert.Equal(t, map[string]interface{}{"geohash": "s0"}, collection.Features[0].Properties)
+	assert.Equal(t, map[string]interface{}{"geohash": "u"}, collection.Features[1].Properties)
+
+	data, err := json.Marshal(HashListGeoJSON(nil))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"type":"FeatureCollection","features":[]}`, string(data))
+}
+
+func TestNearbyGeoJSON(t *testing.T) {
+	origin := Pos{Lat: 10.7769, Lon: 106.7009}
+	hashes := NearbyGeohashList(origin, 5, 5)
+
+	collection := NearbyGeoJSON(origin, 5, hashes)
+	assert.Equal(t, len(hashes)+2, len(collection.Features))
+
+	circle := collection.Features[len(hashes)]
+	assert.Equal(t, "Polygon", circle.Geometry.Type)
+	assert.Equal(t, map[string]interface{}{"radiusKm": 5.0}, circle.Properties)
+
+	ring := circle.Geometry.Coordinates.([][][2]float64)[0]
+	assert.Equal(t, circleVertices+1, len(ring))
+	assert.Equal(t, ring[0], ring[len(ring)-1])
+	for _, c := range ring {
+		assert.InDelta(t, 5, haversineDistance(origin, Pos{Lat: c[1], Lon: c[0]}), 1e-6)
+	}
+
+	// counterclockwise, starting from the north, then to the west
+	assert.Greater(t, ring[0][1], origin.Lat)
+	assert.Less(t, ring[circleVertices/4][0], origin.Lon)
+
+	point := collection.Features[len(hashes)+1]
+	assert.Equal(t, Geometry{Type: "Point", Coordinates: [2]float64{106.7009, 10.7769}}, point.Geometry)
+
+	_, err := json.Marshal(collection)
+	assert.Equal(t, nil, err)
+}
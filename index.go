@@ -0,0 +1,266 @@
+package geohash
+
+import (
+	"errors"
+	"sort"
+)
+
+var (
+	// ErrDuplicatedID is returned when inserting a point with an id that already exists in the index
+	ErrDuplicatedID = errors.New("geohash: duplicated point id")
+
+	// ErrPointNotFound is returned when updating a point that does not exist in the index
+	ErrPointNotFound = errors.New("geohash: point not found")
+)
+
+// Point is a position with an id and an arbitrary value
+type Point struct {
+	ID    string
+	Pos   Pos
+	Value interface{}
+}
+
+// PointDistance is a point found by a radius search, with its haversine distance to the origin in km
+type PointDistance struct {
+	Point
+	Distance float64
+}
+
+// Index is an in-memory point index, points are grouped by their geohashes at the storage precision.
+// It is not safe for concurrent use
+type Index struct {
+	precision uint32
+	points    map[string]indexEntry
+	cells     map[Hash]map[string]struct{}
+}
+
+type indexEntry struct {
+	point Point
+	hash  Hash
+}
+
+// NewIndex creates an empty index storing points at the precision (in characters).
+// Returns ErrInvalidPrecision if the precision is zero or greater than MaxPrecision
+func NewIndex(precision uint32) (*Index, error) {
+	if precision == 0 || precision > MaxPrecision {
+		return nil, ErrInvalidPrecision
+	}
+
+	return &Index{
+		precision: precision,
+		points:    map[string]indexEntry{},
+		cells:     map[Hash]map[string]struct{}{},
+	}, nil
+}
+
+// Precision returns the storage precision of the index
+func (idx *Index) Precision() uint32 {
+	return idx.precision
+}
+
+// Len returns the number of points
+func (idx *Index) Len() int {
+	return len(idx.points)
+}
+
+// Insert adds a new point, returns ErrDuplicatedID if the id already exists
+func (idx *Index) Insert(id string, pos Pos, value interface{}) error {
+	if err := validatePos(pos); err != nil {
+		return err
+	}
+	if _, existed := idx.points[id]; existed {
+		return ErrDuplicatedID
+	}
+
+	idx.put(Point{ID: id, Pos: pos, Value: value})
+	return nil
+}
+
+// Update changes the position and the value of an existing point, moving it to another geohash if needed.
+// Returns ErrPointNotFound if the id does not exist
+func (idx *Index) Update(id string, pos Pos, value interface{}) error {
+	if err := validatePos(pos); err != nil {
+		return err
+	}
+	if _, existed := idx.points[id]; !existed {
+		return ErrPointNotFound
+	}
+
+	idx.Delete(id)
+	idx.put(Point{ID: id, Pos: pos, Value: value})
+	return nil
+}
+
+// Delete removes a point, returns false if the id does not exist
+func (idx *Index) Delete(id string) bool {
+	entry, existed := idx.points[id]
+	if !existed {
+		return false
+	}
+
+	delete(idx.points, id)
+
+	ids := idx.cells[entry.hash]
+	delete(ids, id)
+	if len(ids) == 0 {
+		delete(idx.cells, entry.hash)
+	}
+	return true
+}
+
+// Get returns the point with the id
+func (idx *Index) Get(id string) (Point, bool) {
+	entry, existed := idx.points[id]
+	return entry.point, existed
+}
+
+// SearchRadius returns the points whose haversine distances to the origin are not greater than radius,
+// sorted by the distance then by the id. Radius is in km.
+// The occupied cells are scanned instead if the circle covers more cells than the occupied cells
+func (idx *Index) SearchRadius(origin Pos, radius float64) []PointDistance {
+	if radius < 0 {
+		return nil
+	}
+	if radiusCoveringSize(origin, radius, idx.precision) > uint64(idx.cellCount()) {
+		return radiusPointsByScanning(idx, idx.precision, origin, radius)
+	}
+	return radiusPoints(idx, NearbyGeohashList(origin, radius, idx.precision), origin, radius)
+}
+
+// SearchBounds returns the points inside the bounds, sorted by the id.
+// The occupied cells are scanned instead if the bounds cover more cells than the occupied cells
+func (idx *Index) SearchBounds(bounds Bounds) []Point {
+	if boundsCoveringSize(bounds, idx.precision) > uint64(idx.cellCount()) {
+		return boundsPointsByScanning(idx, idx.precision, bounds)
+	}
+	return boundsPoints(idx, CoverRectangle(bounds, idx.precision), bounds)
+}
+
+func (idx *Index) cellCount() int {
+	return len(idx.cells)
+}
+
+func (idx *Index) forEachCell(fn func(h Hash)) {
+	for h := range idx.cells {
+		fn(h)
+	}
+}
+
+func (idx *Index) forEachPointInCell(h Hash, fn func(p Point)) {
+	for id := range idx.cells[h] {
+		fn(idx.points[id].point)
+	}
+}
+
+func (idx *Index) put(p Point) {
+	h := ComputeGeohash(p.Pos, idx.precision)
+	idx.points[p.ID] = indexEntry{point: p, hash: h}
+
+	ids, existed := idx.cells[h]
+	if !existed {
+		ids = map[string]struct{}{}
+		idx.cells[h] = ids
+	}
+	ids[p.ID] = struct{}{}
+}
+
+// pointCells is a set of points grouped by their geohashes at the same precision
+type pointCells interface {
+	cellCount() int
+	forEachCell(fn func(h Hash))
+	forEachPointInCell(h Hash, fn func(p Point))
+}
+
+var _ pointCells = &Index{}
+
+// boundsCoveringSize returns the number of geohashes returned by CoverRectangle, without computing them
+func boundsCoveringSize(bounds Bounds, precision uint32) uint64 {
+	_, latSteps, lonSteps, ok := rectangleSteps(bounds, precision)
+	if !ok {
+		return 0
+	}
+	return (uint64(latSteps) + 1) * (uint64(lonSteps) + 1)
+}
+
+// radiusCoveringSize returns the number of geohashes covering the bounding box of the circle,
+// an upper bound of the geohashes visited by NearbyGeohashList
+func radiusCoveringSize(origin Pos, radius float64, precision uint32) uint64 {
+	return boundsCoveringSize(Circle{Center: origin, Radius: radius}.RectBound(), precision)
+}
+
+// radiusPointsByScanning is the same as radiusPoints with the geohashes of NearbyGeohashList,
+// but only the occupied cells are checked
+func radiusPointsByScanning(cells pointCells, precision uint32, origin Pos, radius float64) []PointDistance {
+	originHash := ComputeGeohash(origin, precision)
+
+	var hashes []Hash
+	cells.forEachCell(func(h Hash) {
+		if h == originHash || minDistanceToGeohash(origin, h) <= radius {
+			hashes = append(hashes, h)
+		}
+	})
+	return radiusPoints(cells, hashes, origin, radius)
+}
+
+// boundsPointsByScanning is the same as boundsPoints with the geohashes of CoverRectangle,
+// but only the occupied cells are checked
+func boundsPointsByScanning(cells pointCells, precision uint32, bounds Bounds) []Point {
+	start, latSteps, lonSteps, ok := rectangleSteps(bounds, precision)
+	if !ok {
+		return nil
+	}
+
+	_, lonPrecision := bitPrecisions(start.bits)
+	lonMask := uint64(1)<<lonPrecision - 1
+
+	var hashes []Hash
+	cells.forEachCell(func(h Hash) {
+		// the same rows and columns as CoverRectangle, the columns may wrap around the antimeridian
+		if h.lat >= start.lat && h.lat-start.lat <= uint64(latSteps) && (h.lon-start.lon)&lonMask <= uint64(lonSteps) {
+			hashes = append(hashes, h)
+		}
+	})
+	return boundsPoints(cells, hashes, bounds)
+}
+
+// radiusPoints filters the points of the geohashes by their haversine distances to the origin
+func radiusPoints(cells pointCells, hashes []Hash, origin Pos, radius float64) []PointDistance {
+	var result []PointDistance
+	for _, h := range hashes {
+		cells.forEachPointInCell(h, func(p Point) {
+			d := haversineDistance(origin, p.Pos)
+			if d <= radius {
+				result = append(result, PointDistance{Point: p, Distance: d})
+			}
+		})
+	}
+
+	sortPointDistances(result)
+	return result
+}
+
+// boundsPoints filters the points of the geohashes by the bounds
+func boundsPoints(cells pointCells, hashes []Hash, bounds Bounds) []Point {
+	var result []Point
+	for _, h := range hashes {
+		cells.forEachPointInCell(h, func(p Point) {
+			if bounds.Contains(p.Pos) {
+				result = append(result, p)
+			}
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+func sortPointDistances(points []PointDistance) {
+	sort.Slice(points, func(i, j int) bool {
+		if points[i].Distance != points[j].Distance {
+			return points[i].Distance < points[j].Distance
+		}
+		return points[i].ID < points[j].ID
+	})
+}
@@ -0,0 +1,258 @@
+package geohash
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func newTestIndex(t *testing.T, precision uint32) *Index {
+	idx, err := NewIndex(precision)
+	assert.Equal(t, nil, err)
+	return idx
+}
+
+func TestNewIndex(t *testing.T) {
+	idx, err := NewIndex(0)
+	assert.Equal(t, ErrInvalidPrecision, err)
+	assert.Nil(t, idx)
+
+	_, err = NewIndex(MaxPrecision + 1)
+	assert.Equal(t, ErrInvalidPrecision, err)
+
+	idx = newTestIndex(t, 6)
+	assert.Equal(t, uint32(6), idx.Precision())
+	assert.Equal(t, 0, idx.Len())
+}
+
+func TestIndex_Insert_Update_Delete(t *testing.T) {
+	idx := newTestIndex(t, 6)
+
+	err := idx.Insert("a", Pos{Lat: 10.7769, Lon: 106.7009}, 1)
+	assert.Equal(t, nil, err)
+
+	err = idx.Insert("a", Pos{Lat: 10, Lon: 106}, 2)
+	assert.Equal(t, ErrDuplicatedID, err)
+
+	err = idx.Insert("b", Pos{Lat: 91, Lon: 106}, 2)
+	assert.Equal(t, ErrInvalidLatitude, err)
+	assert.Equal(t, 1, idx.Len())
+
+	p, ok := idx.Get("a")
+	assert.Equal(t, true, ok)
+	assert.Equal(t, Point{ID: "a", Pos: Pos{Lat: 10.7769, Lon: 106.7009}, Value: 1}, p)
+
+	_, ok = idx.Get("b")
+	assert.Equal(t, false, ok)
+
+	err = idx.Update("b", Pos{Lat: 10, Lon: 106}, 3)
+	assert.Equal(t, ErrPointNotFound, err)
+
+	err = idx.Update("a", Pos{Lat: 21.0285, Lon: 105.8542}, 4)
+	assert.Equal(t, nil, err)
+
+	p, _ = idx.Get("a")
+	assert.Equal(t, Point{ID: "a", Pos: Pos{Lat: 21.0285, Lon: 105.8542}, Value: 4}, p)
+	assert.Equal(t, []Hash{ComputeGeohash(p.Pos, 6)}, indexCells(idx))
+
+	err = idx.Update("a", Pos{Lat: 10, Lon: 181}, 5)
+	assert.Equal(t, ErrInvalidLongitude, err)
+
+	assert.Equal(t, true, idx.Delete("a"))
+	assert.Equal(t, false, idx.Delete("a"))
+	assert.Equal(t, 0, idx.Len())
+	assert.Equal(t, []Hash(nil), indexCells(idx))
+}
+
+func indexCells(idx *Index) []Hash {
+	var result []Hash
+	for h := range idx.cells {
+		result = append(result, h)
+	}
+	return result
+}
+
+func TestIndex_SearchRadius(t *testing.T) {
+	idx := newTestIndex(t, 5)
+
+	origin := Pos{Lat: 10.7769, Lon: 106.7009}
+	_ = idx.Insert("origin", origin, nil)
+	_ = idx.Insert("east", Pos{Lat: 10.7769, Lon: 106.72}, nil)
+	_ = idx.Insert("north", Pos{Lat: 10.8, Lon: 106.7009}, nil)
+	_ = idx.Insert("far", Pos{Lat: 11.5, Lon: 106.7009}, nil)
+
+	result := idx.SearchRadius(origin, 5)
+
+	var ids []string
+	for _, p := range result {
+		ids = append(ids, p.ID)
+		assert.Equal(t, haversineDistance(origin, p.Pos), p.Distance)
+	}
+	assert.Equal(t, []string{"origin", "east", "north"}, ids)
+	assert.Equal(t, 0.0, result[0].Distance)
+
+	assert.Equal(t, 1, len(idx.SearchRadius(origin, 1)))
+	assert.Equal(t, 4, len(idx.SearchRadius(origin, 100)))
+	assert.Equal(t, []PointDistance(nil), idx.SearchRadius(origin, -1))
+}
+
+func TestIndex_SearchBounds(t *testing.T) {
+	idx := newTestIndex(t, 4)
+
+	_ = idx.Insert("a", Pos{Lat: 10.5, Lon: 179.9}, nil)
+	_ = idx.Insert("b", Pos{Lat: 10.5, Lon: -179.9}, nil)
+	_ = idx.Insert("c", Pos{Lat: 10.5, Lon: 0}, nil)
+	_ = idx.Insert("d", Pos{Lat: 12, Lon: 179.9}, nil)
+
+	result := idx.SearchBounds(Bounds{
+		Min: Pos{Lat: 10, Lon: 179},
+		Max: Pos{Lat: 11, Lon: -179},
+	})
+	assert.Equal(t, []Point{
+		{ID: "a", Pos: Pos{Lat: 10.5, Lon: 179.9}},
+		{ID: "b", Pos: Pos{Lat: 10.5, Lon: -179.9}},
+	}, result)
+
+	result = idx.SearchBounds(Bounds{
+		Min: Pos{Lat: 10, Lon: -1},
+		Max: Pos{Lat: 11, Lon: 1},
+	})
+	assert.Equal(t, []Point{{ID: "c", Pos: Pos{Lat: 10.5, Lon: 0}}}, result)
+}
+
+func TestIndex_Properties_Based_Testing(t *testing.T) {
+	seed := time.Now().Unix()
+	fmt.Println("SEED:", seed)
+	rand.Seed(seed)
+
+	idx := newTestIndex(t, uint32(randInt(4, 6)))
+
+	var points []Point
+	for i := 0; i < 2000; i++ {
+		p := Point{
+			ID:  strconv.Itoa(i),
+			Pos: Pos{Lat: mathRand(10, 11), Lon: mathRand(106, 107)},
+		}
+		points = append(points, p)
+		assert.Equal(t, nil, idx.Insert(p.ID, p.Pos, nil))
+	}
+
+	for i := 0; i < 20; i++ {
+		origin := Pos{Lat: mathRand(10, 11), Lon: mathRand(106, 107)}
+		radius := mathRand(0, 20)
+
+		var expected []string
+		for _, p := range points {
+			if haversineDistance(origin, p.Pos) <= radius {
+				expected = append(expected, p.ID)
+			}
+		}
+		sort.Strings(expected)
+
+		var ids []string
+		for _, p := range idx.SearchRadius(origin, radius) {
+			ids = append(ids, p.ID)
+		}
+		sort.Strings(ids)
+
+		assert.Equal(t, expected, ids)
+	}
+}
+
+func TestIndex_Search_By_Scanning(t *testing.T) {
+	seed := time.Now().Unix()
+	fmt.Println("SEED:", seed)
+	rand.Seed(seed)
+
+	// the coverings have far more cells than the occupied cells
+	idx := newTestIndex(t, 9)
+
+	var points []Point
+	for i := 0; i < 300; i++ {
+		p := Point{
+			ID:  strconv.Itoa(i),
+			Pos: Pos{Lat: mathRand(-90, 90), Lon: mathRand(-180, 180)},
+		}
+		if i%3 == 0 {
+			p.Pos.Lat = mathRand(89.9, 90)
+		}
+		points = append(points, p)
+		assert.Equal(t, nil, idx.Insert(p.ID, p.Pos, nil))
+	}
+
+	for i := 0; i < 50; i++ {
+		origin := Pos{Lat: mathRand(-90, 90), Lon: mathRand(-180, 180)}
+		if i%2 == 0 {
+			origin.Lat = mathRand(89.9, 90)
+		}
+		radius := mathRand(0, 3000)
+
+		var expectedRadius []string
+		for _, p := range points {
+			if haversineDistance(origin, p.Pos) <= radius {
+				expectedRadius = append(expectedRadius, p.ID)
+			}
+		}
+		sort.Strings(expectedRadius)
+
+		var ids []string
+		for _, p := range idx.SearchRadius(origin, radius) {
+			ids = append(ids, p.ID)
+		}
+		sort.Strings(ids)
+		assert.Equal(t, expectedRadius, ids)
+
+		bounds := Bounds{
+			Min: Pos{Lat: mathRand(-90, 90), Lon: mathRand(-190, 180)},
+			Max: Pos{Lat: mathRand(-90, 90), Lon: mathRand(-180, 190)},
+		}
+
+		var expectedBounds []string
+		for _, p := range points {
+			if bounds.Contains(p.Pos) {
+				expectedBounds = append(expectedBounds, p.ID)
+			}
+		}
+		sort.Strings(expectedBounds)
+
+		ids = nil
+		for _, p := range idx.SearchBounds(bounds) {
+			ids = append(ids, p.ID)
+		}
+		assert.Equal(t, expectedBounds, ids)
+	}
+}
+
+func TestIndex_Search_By_Scanning_Same_As_Covering(t *testing.T) {
+	seed := time.Now().Unix()
+	fmt.Println("SEED:", seed)
+	rand.Seed(seed)
+
+	idx := newTestIndex(t, 3)
+	for i := 0; i < 3000; i++ {
+		pos := Pos{Lat: mathRand(-30, 30), Lon: mathRand(150, 210)}
+		assert.Equal(t, nil, idx.Insert(strconv.Itoa(i), Pos{Lat: pos.Lat, Lon: wrapLon(pos.Lon)}, nil))
+	}
+
+	for i := 0; i < 50; i++ {
+		origin := Pos{Lat: mathRand(-30, 30), Lon: wrapLon(mathRand(150, 210))}
+		radius := mathRand(0, 1000)
+		assert.Equal(t,
+			radiusPoints(idx, NearbyGeohashList(origin, radius, 3), origin, radius),
+			radiusPointsByScanning(idx, 3, origin, radius),
+		)
+
+		bounds := Bounds{
+			Min: origin,
+			Max: Pos{Lat: origin.Lat + mathRand(0, 10), Lon: wrapLon(origin.Lon + mathRand(0, 10))},
+		}
+		assert.Equal(t,
+			boundsPoints(idx, CoverRectangle(bounds, 3), bounds),
+			boundsPointsByScanning(idx, 3, bounds),
+		)
+	}
+}
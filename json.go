@@ -0,0 +1,172 @@
+package geohash
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNotCharacterAligned is returned when encoding a geohash whose number of bits is not a multiple of 5
+	ErrNotCharacterAligned = errors.New("geohash: number of bits is not a multiple of 5")
+
+	// ErrMissingField is returned when decoding a JSON object without a required field
+	ErrMissingField = errors.New("geohash: missing field")
+
+	// ErrInvalidRectangle is returned when decoding a rectangle whose corners are not aligned
+	// or whose bottom left corner is not below and to the left of its top right corner
+	ErrInvalidRectangle = errors.New("geohash: invalid rectangle")
+)
+
+var jsonNull = []byte("null")
+
+// MarshalText encodes the geohash as its string, the empty geohash is encoded as an empty string.
+// Returns ErrNotCharacterAligned if the number of bits is not a multiple of 5
+func (h Hash) MarshalText() ([]byte, error) {
+	if h.bits%5 != 0 {
+		return nil, ErrNotCharacterAligned
+	}
+	return []byte(h.String()), nil
+}
+
+// UnmarshalText decodes the geohash string, the same as Parse except that an empty string is the empty geohash
+func (h *Hash) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*h = Hash{}
+		return nil
+	}
+
+	result, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*h = result
+	return nil
+}
+
+// MarshalJSON encodes the geohash as a JSON string
+func (h Hash) MarshalJSON() ([]byte, error) {
+	text, err := h.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON decodes a JSON string, null is ignored
+func (h *Hash) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, jsonNull) {
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return h.UnmarshalText([]byte(s))
+}
+
+type posJSON struct {
+	Lat *float64 `json:"lat"`
+	Lon *float64 `json:"lon"`
+}
+
+// MarshalJSON encodes the position as {"lat": ..., "lon": ...}, the position is not validated
+func (p Pos) MarshalJSON() ([]byte, error) {
+	return json.Marshal(posJSON{Lat: &p.Lat, Lon: &p.Lon})
+}
+
+// UnmarshalJSON decodes {"lat": ..., "lon": ...}, both fields are required, unknown fields are rejected
+// and the position must be valid, null is ignored
+func (p *Pos) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, jsonNull) {
+		return nil
+	}
+
+	var value posJSON
+	if err := decodeStrictJSON(data, &value); err != nil {
+		return err
+	}
+	if value.Lat == nil {
+		return fmt.Errorf("%w: lat", ErrMissingField)
+	}
+	if value.Lon == nil {
+		return fmt.Errorf("%w: lon", ErrMissingField)
+	}
+
+	pos := Pos{Lat: *value.Lat, Lon: *value.Lon}
+	if err := validatePos(pos); err != nil {
+		return err
+	}
+	*p = pos
+	return nil
+}
+
+type rectangleJSON struct {
+	BottomLeft  *Pos `json:"bottomLeft"`
+	BottomRight *Pos `json:"bottomRight"`
+	TopLeft     *Pos `json:"topLeft"`
+	TopRight    *Pos `json:"topRight"`
+}
+
+// MarshalJSON encodes the 4 corners as {"bottomLeft": ..., "bottomRight": ..., "topLeft": ..., "topRight": ...}
+func (r Rectangle) MarshalJSON() ([]byte, error) {
+	return json.Marshal(rectangleJSON{
+		BottomLeft:  &r.BottomLeft,
+		BottomRight: &r.BottomRight,
+		TopLeft:     &r.TopLeft,
+		TopRight:    &r.TopRight,
+	})
+}
+
+// UnmarshalJSON decodes the 4 corners, all corners are required, unknown fields are rejected
+// and the corners must form a rectangle, null is ignored
+func (r *Rectangle) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, jsonNull) {
+		return nil
+	}
+
+	var value rectangleJSON
+	if err := decodeStrictJSON(data, &value); err != nil {
+		return err
+	}
+
+	corners := []struct {
+		name string
+		pos  *Pos
+	}{
+		{name: "bottomLeft", pos: value.BottomLeft},
+		{name: "bottomRight", pos: value.BottomRight},
+		{name: "topLeft", pos: value.TopLeft},
+		{name: "topRight", pos: value.TopRight},
+	}
+	for _, corner := range corners {
+		if corner.pos == nil {
+			return fmt.Errorf("%w: %s", ErrMissingField, corner.name)
+		}
+	}
+
+	rec := Rectangle{
+		BottomLeft:  *value.BottomLeft,
+		BottomRight: *value.BottomRight,
+		TopLeft:     *value.TopLeft,
+		TopRight:    *value.TopRight,
+	}
+	if rec.BottomLeft.Lat != rec.BottomRight.Lat || rec.TopLeft.Lat != rec.TopRight.Lat ||
+		rec.BottomLeft.Lon != rec.TopLeft.Lon || rec.BottomRight.Lon != rec.TopRight.Lon {
+		return ErrInvalidRectangle
+	}
+	if rec.BottomLeft.Lat > rec.TopLeft.Lat || rec.BottomLeft.Lon > rec.BottomRight.Lon {
+		return ErrInvalidRectangle
+	}
+
+	*r = rec
+	return nil
+}
+
+func decodeStrictJSON(data []byte, value interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(value)
+}
@@ -0,0 +1,149 @@
+package geohash
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestHash_MarshalText(t *testing.T) {
+	text, err := mustParse("w3gv2").MarshalText()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "w3gv2", string(text))
+
+	text, err = Hash{}.MarshalText()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "", string(text))
+
+	_, err = ComputeGeohashBits(Pos{}, 13).MarshalText()
+	assert.Equal(t, ErrNotCharacterAligned, err)
+
+	var h Hash
+	assert.Equal(t, nil, h.UnmarshalText([]byte("w3gv2")))
+	assert.Equal(t, mustParse("w3gv2"), h)
+
+	assert.Equal(t, nil, h.UnmarshalText(nil))
+	assert.Equal(t, Hash{}, h)
+
+	h = mustParse("s")
+	assert.Equal(t, &InvalidCharacterError{Char: 'a', Index: 2}, h.UnmarshalText([]byte("w3a")))
+	assert.Equal(t, mustParse("s"), h)
+}
+
+func TestHash_JSON(t *testing.T) {
+	type value struct {
+		Hash  Hash         `json:"hash"`
+		Empty Hash         `json:"empty"`
+		Keys  map[Hash]int `json:"keys"`
+		List  []Hash       `json:"list"`
+		Ptr   *Hash        `json:"ptr"`
+	}
+
+	data, err := json.Marshal(value{
+		Hash: mustParse("w3gv2"),
+		Keys: map[Hash]int{mustParse("s0"): 1},
+		List: []Hash{mustParse("u"), mustParse("zz")},
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"hash":"w3gv2","empty":"","keys":{"s0":1},"list":["u","zz"],"ptr":null}`, string(data))
+
+	var result value
+	err = json.Unmarshal(data, &result)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, value{
+		Hash: mustParse("w3gv2"),
+		Keys: map[Hash]int{mustParse("s0"): 1},
+		List: []Hash{mustParse("u"), mustParse("zz")},
+	}, result)
+
+	var h Hash
+	assert.Equal(t, ErrGeohashTooLong, json.Unmarshal([]byte(`"0123456789bcdefghjkmn"`), &h))
+	assert.Error(t, json.Unmarshal([]byte(`123`), &h))
+
+	h = mustParse("s")
+	assert.Equal(t, nil, json.Unmarshal([]byte(`null`), &h))
+	assert.Equal(t, mustParse("s"), h)
+
+	_, err = json.Marshal(ComputeGeohashBits(Pos{}, 13))
+	assert.True(t, errors.Is(err, ErrNotCharacterAligned))
+}
+
+func TestPos_JSON(t *testing.T) {
+	data, err := json.Marshal(Pos{Lat: 10.7769, Lon: 106.7009})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"lat":10.7769,"lon":106.7009}`, string(data))
+
+	// only decoding is strict
+	data, err = json.Marshal(struct {
+		Center Pos `json:"center"`
+	}{Center: Pos{Lat: 91, Lon: 190}})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"center":{"lat":91,"lon":190}}`, string(data))
+
+	var p Pos
+	assert.Equal(t, nil, json.Unmarshal([]byte(`{"lat": 10.7769, "lon": 106.7009}`), &p))
+	assert.Equal(t, Pos{Lat: 10.7769, Lon: 106.7009}, p)
+
+	assert.Equal(t, nil, json.Unmarshal([]byte(`null`), &p))
+	assert.Equal(t, Pos{Lat: 10.7769, Lon: 106.7009}, p)
+
+	err = json.Unmarshal([]byte(`{"lat": 10}`), &p)
+	assert.True(t, errors.Is(err, ErrMissingField))
+	assert.Equal(t, "geohash: missing field: lon", err.Error())
+
+	err = json.Unmarshal([]byte(`{"lon": 10}`), &p)
+	assert.Equal(t, "geohash: missing field: lat", err.Error())
+
+	assert.Equal(t, ErrInvalidLatitude, json.Unmarshal([]byte(`{"lat": -90.5, "lon": 10}`), &p))
+	assert.Equal(t, ErrInvalidLongitude, json.Unmarshal([]byte(`{"lat": 10, "lon": 180.5}`), &p))
+	assert.Error(t, json.Unmarshal([]byte(`{"lat": 10, "lon": 10, "alt": 1}`), &p))
+	assert.Error(t, json.Unmarshal([]byte(`{"lat": "10", "lon": 10}`), &p))
+	assert.Error(t, json.Unmarshal([]byte(`[10, 10]`), &p))
+
+	assert.Equal(t, Pos{Lat: 10.7769, Lon: 106.7009}, p)
+}
+
+func TestRectangle_JSON(t *testing.T) {
+	rec := mustParse("s0000").Rec()
+
+	data, err := json.Marshal(rec)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"bottomLeft":{"lat":0,"lon":0},"bottomRight":{"lat":0,"lon":0.0439453125},`+
+		`"topLeft":{"lat":0.0439453125,"lon":0},"topRight":{"lat":0.0439453125,"lon":0.0439453125}}`, string(data))
+
+	var result Rectangle
+	assert.Equal(t, nil, json.Unmarshal(data, &result))
+	assert.Equal(t, rec, result)
+
+	rec = mustParse("zz").Rec()
+	data, _ = json.Marshal(rec)
+	assert.Equal(t, nil, json.Unmarshal(data, &result))
+	assert.Equal(t, rec, result)
+
+	err = json.Unmarshal([]byte(`{"bottomLeft":{"lat":0,"lon":0},"bottomRight":{"lat":0,"lon":1},`+
+		`"topLeft":{"lat":1,"lon":0}}`), &result)
+	assert.Equal(t, "geohash: missing field: topRight", err.Error())
+
+	err = json.Unmarshal([]byte(`{"bottomLeft":{"lat":0,"lon":0},"bottomRight":{"lat":0,"lon":1},`+
+		`"topLeft":{"lat":1,"lon":0},"topRight":null}`), &result)
+	assert.True(t, errors.Is(err, ErrMissingField))
+
+	err = json.Unmarshal([]byte(`{"bottomLeft":{"lat":0,"lon":0},"bottomRight":{"lat":0,"lon":1},`+
+		`"topLeft":{"lat":1,"lon":0},"topRight":{"lat":1,"lon":2}}`), &result)
+	assert.Equal(t, ErrInvalidRectangle, err)
+
+	err = json.Unmarshal([]byte(`{"bottomLeft":{"lat":1,"lon":0},"bottomRight":{"lat":1,"lon":1},`+
+		`"topLeft":{"lat":0,"lon":0},"topRight":{"lat":0,"lon":1}}`), &result)
+	assert.Equal(t, ErrInvalidRectangle, err)
+
+	err = json.Unmarshal([]byte(`{"bottomLeft":{"lat":0,"lon":0},"bottomRight":{"lat":0,"lon":1},`+
+		`"topLeft":{"lat":1,"lon":0},"topRight":{"lat":1,"lon":181}}`), &result)
+	assert.Equal(t, ErrInvalidLongitude, err)
+
+	err = json.Unmarshal([]byte(`{"bottomLeft":{"lat":0,"lon":0},"bottomRight":{"lat":0,"lon":1},`+
+		`"topLeft":{"lat":1,"lon":0},"topRight":{"lat":1,"lon":1},"center":{"lat":0.5,"lon":0.5}}`), &result)
+	assert.Error(t, err)
+
+	assert.Equal(t, mustParse("zz").Rec(), result)
+}
@@ -0,0 +1,143 @@
+package geohash
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// Nearest returns the k nearest points to the origin, sorted by their haversine distances then by their ids.
+// It expands rings of geohashes outward from the geohash containing the origin, skipping cells whose
+// min distances are greater than the current k-th distance, and stops when the nearest cell of a ring
+// is not closer than the k-th distance. When the rings reach a pole, wrap around the whole longitude range
+// or have more cells than the occupied cells of the index, the occupied cells are scanned instead
+func (idx *Index) Nearest(origin Pos, k int) []PointDistance {
+	return nearest(idx, idx.precision, origin, k)
+}
+
+func nearest(cells pointCells, precision uint32, origin Pos, k int) []PointDistance {
+	if k <= 0 || cells.cellCount() == 0 {
+		return nil
+	}
+
+	h := ComputeGeohash(origin, precision)
+
+	top := &pointDistanceHeap{}
+	pushCellPoints(cells, top, k, origin, h)
+
+	for distance := 1; ; distance++ {
+		if 8*distance > cells.cellCount() {
+			return nearestByScanning(cells, origin, k)
+		}
+
+		ringMinDistance := math.Inf(1)
+
+		offset := posOffset{lat: 0, lon: distance}
+		ok := true
+		for ; ok; offset, ok = nearbyNext(offset, distance) {
+			cell, inside := h.ringOffset(offset)
+			if !inside {
+				// the ring is no longer a closed boundary around the origin
+				return nearestByScanning(cells, origin, k)
+			}
+
+			d := minDistanceToGeohash(origin, cell)
+			ringMinDistance = math.Min(ringMinDistance, d)
+
+			if top.Len() == k && d > (*top)[0].Distance {
+				continue
+			}
+			pushCellPoints(cells, top, k, origin, cell)
+		}
+
+		// the points outside of the rings are not closer than the nearest cell of the last ring
+		if top.Len() == k && (*top)[0].Distance <= ringMinDistance {
+			return top.sorted()
+		}
+	}
+}
+
+func nearestByScanning(cells pointCells, origin Pos, k int) []PointDistance {
+	type cellDistance struct {
+		hash     Hash
+		distance float64
+	}
+
+	sortedCells := make([]cellDistance, 0, cells.cellCount())
+	cells.forEachCell(func(h Hash) {
+		sortedCells = append(sortedCells, cellDistance{
+			hash:     h,
+			distance: minDistanceToGeohash(origin, h),
+		})
+	})
+	sort.Slice(sortedCells, func(i, j int) bool {
+		return sortedCells[i].distance < sortedCells[j].distance
+	})
+
+	top := &pointDistanceHeap{}
+	for _, cell := range sortedCells {
+		if top.Len() == k && cell.distance > (*top)[0].Distance {
+			break
+		}
+		pushCellPoints(cells, top, k, origin, cell.hash)
+	}
+	return top.sorted()
+}
+
+// pushCellPoints keeps the k nearest points in the heap
+func pushCellPoints(cells pointCells, top *pointDistanceHeap, k int, origin Pos, h Hash) {
+	cells.forEachPointInCell(h, func(p Point) {
+		candidate := PointDistance{
+			Point:    p,
+			Distance: haversineDistance(origin, p.Pos),
+		}
+
+		if top.Len() < k {
+			heap.Push(top, candidate)
+			return
+		}
+		if lessPointDistance(candidate, (*top)[0]) {
+			(*top)[0] = candidate
+			heap.Fix(top, 0)
+		}
+	})
+}
+
+func lessPointDistance(a, b PointDistance) bool {
+	if a.Distance != b.Distance {
+		return a.Distance < b.Distance
+	}
+	return a.ID < b.ID
+}
+
+// pointDistanceHeap is a max heap, the farthest point is at the top
+type pointDistanceHeap []PointDistance
+
+func (h pointDistanceHeap) Len() int {
+	return len(h)
+}
+
+func (h pointDistanceHeap) Less(i, j int) bool {
+	return lessPointDistance(h[j], h[i])
+}
+
+func (h pointDistanceHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+}
+
+func (h *pointDistanceHeap) Push(x interface{}) {
+	*h = append(*h, x.(PointDistance))
+}
+
+func (h *pointDistanceHeap) Pop() interface{} {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
+
+func (h *pointDistanceHeap) sorted() []PointDistance {
+	result := []PointDistance(*h)
+	sortPointDistances(result)
+	return result
+}
@@ -0,0 +1,97 @@
+package geohash
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func nearestIDs(points []PointDistance) []string {
+	var ids []string
+	for _, p := range points {
+		ids = append(ids, p.ID)
+	}
+	return ids
+}
+
+func TestIndex_Nearest(t *testing.T) {
+	idx := newTestIndex(t, 5)
+
+	origin := Pos{Lat: 10.7769, Lon: 106.7009}
+	assert.Equal(t, []PointDistance(nil), idx.Nearest(origin, 3))
+
+	_ = idx.Insert("a", Pos{Lat: 10.7769, Lon: 106.71}, nil)
+	_ = idx.Insert("b", Pos{Lat: 10.79, Lon: 106.7009}, nil)
+	_ = idx.Insert("c", Pos{Lat: 10.7769, Lon: 106.8}, nil)
+	_ = idx.Insert("d", Pos{Lat: 12, Lon: 106.7009}, nil)
+	_ = idx.Insert("e", Pos{Lat: 10.7769, Lon: 106.70}, nil)
+
+	result := idx.Nearest(origin, 3)
+	assert.Equal(t, []string{"e", "a", "b"}, nearestIDs(result))
+	for _, p := range result {
+		assert.Equal(t, haversineDistance(origin, p.Pos), p.Distance)
+	}
+
+	assert.Equal(t, []string{"e", "a", "b", "c", "d"}, nearestIDs(idx.Nearest(origin, 10)))
+	assert.Equal(t, []PointDistance(nil), idx.Nearest(origin, 0))
+}
+
+func TestIndex_Nearest_Poles_And_Antimeridian(t *testing.T) {
+	idx := newTestIndex(t, 4)
+
+	_ = idx.Insert("across-pole", Pos{Lat: 89.9, Lon: -170}, nil)
+	_ = idx.Insert("same-side", Pos{Lat: 89, Lon: 10}, nil)
+	_ = idx.Insert("east", Pos{Lat: 10, Lon: -179.9}, nil)
+	_ = idx.Insert("west", Pos{Lat: 10, Lon: 179.7}, nil)
+
+	assert.Equal(t, []string{"across-pole", "same-side"}, nearestIDs(idx.Nearest(Pos{Lat: 89.9, Lon: 10}, 2)))
+	assert.Equal(t, []string{"east", "west"}, nearestIDs(idx.Nearest(Pos{Lat: 10, Lon: 179.95}, 2)))
+}
+
+func TestIndex_Nearest_Properties_Based_Testing(t *testing.T) {
+	seed := time.Now().Unix()
+	fmt.Println("SEED:", seed)
+	rand.Seed(seed)
+
+	for i := 0; i < 20; i++ {
+		idx := newTestIndex(t, uint32(randInt(3, 7)))
+
+		center := Pos{Lat: mathRand(-80, 80), Lon: mathRand(-180, 180)}
+		size := mathRand(0.1, 5)
+
+		var points []Point
+		for k := 0; k < randInt(1, 2000); k++ {
+			p := Point{
+				ID: strconv.Itoa(k),
+				Pos: Pos{
+					Lat: center.Lat + mathRand(-size, size),
+					Lon: wrapLon(center.Lon + mathRand(-size, size)),
+				},
+			}
+			points = append(points, p)
+			assert.Equal(t, nil, idx.Insert(p.ID, p.Pos, nil))
+		}
+
+		for k := 0; k < 10; k++ {
+			origin := Pos{
+				Lat: center.Lat + mathRand(-size, size),
+				Lon: wrapLon(center.Lon + mathRand(-size, size)),
+			}
+			count := randInt(1, 20)
+
+			var expected []PointDistance
+			for _, p := range points {
+				expected = append(expected, PointDistance{Point: p, Distance: haversineDistance(origin, p.Pos)})
+			}
+			sortPointDistances(expected)
+			if len(expected) > count {
+				expected = expected[:count]
+			}
+
+			assert.Equal(t, expected, idx.Nearest(origin, count))
+		}
+	}
+}
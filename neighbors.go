@@ -0,0 +1,104 @@
+package geohash
+
+// Direction is one of the 8 directions of the adjacent geohashes
+type Direction int
+
+const (
+	North Direction = iota
+	NorthEast
+	East
+	SouthEast
+	South
+	SouthWest
+	West
+	NorthWest
+)
+
+var directionNames = [8]string{
+	"North", "NorthEast", "East", "SouthEast",
+	"South", "SouthWest", "West", "NorthWest",
+}
+
+var directionOffsets = [8]posOffset{
+	{lat: 1, lon: 0},
+	{lat: 1, lon: 1},
+	{lat: 0, lon: 1},
+	{lat: -1, lon: 1},
+	{lat: -1, lon: 0},
+	{lat: -1, lon: -1},
+	{lat: 0, lon: -1},
+	{lat: 1, lon: -1},
+}
+
+func (d Direction) String() string {
+	if d < North || d > NorthWest {
+		return "Unknown"
+	}
+	return directionNames[d]
+}
+
+// Neighbor returns the adjacent geohash in the direction, wrapping around the antimeridian.
+// Returns false if the geohash touches a pole and the direction crosses that pole
+func (h Hash) Neighbor(direction Direction) (Hash, bool) {
+	if direction < North || direction > NorthWest {
+		return Hash{}, false
+	}
+	offset := directionOffsets[direction]
+	return h.Offset(offset.lat, offset.lon)
+}
+
+// Neighbors returns the 8 adjacent geohashes indexed by Direction, the same as Neighbor for each direction.
+// ok[d] is false if the geohash touches a pole and the direction d crosses that pole
+func (h Hash) Neighbors() (neighbors [8]Hash, ok [8]bool) {
+	for d := North; d <= NorthWest; d++ {
+		neighbors[d], ok[d] = h.Neighbor(d)
+	}
+	return neighbors, ok
+}
+
+// Ring returns the geohashes at Chebyshev distance exactly k, counterclockwise starting from the east.
+// Cells beyond the poles are skipped and each geohash is returned at most once
+// even if the ring wraps around the whole longitude range. Ring(0) returns the geohash itself
+func (h Hash) Ring(k int) []Hash {
+	if k < 0 {
+		return nil
+	}
+	if k == 0 {
+		return []Hash{h}
+	}
+
+	var result []Hash
+
+	offset := posOffset{lat: 0, lon: k}
+	ok := true
+	for ; ok; offset, ok = nearbyNext(offset, k) {
+		newHash, inside := h.ringOffset(offset)
+		if !inside {
+			continue
+		}
+		result = append(result, newHash)
+	}
+	return result
+}
+
+// Disk returns the geohashes at Chebyshev distance at most k, ring by ring from the geohash itself
+func (h Hash) Disk(k int) []Hash {
+	var result []Hash
+	for distance := 0; distance <= k; distance++ {
+		result = append(result, h.Ring(distance)...)
+	}
+	return result
+}
+
+// ringOffset computes the geohash at the offset, returns false if the offset crosses a pole
+// or its longitude offset is outside of the window [-(lonCount - 1) / 2, lonCount / 2],
+// so that each column is visited only once
+func (h Hash) ringOffset(offset posOffset) (Hash, bool) {
+	_, lonPrecision := bitPrecisions(h.bits)
+	lonCount := 1 << lonPrecision
+
+	if offset.lon < -(lonCount-1)/2 || offset.lon > lonCount/2 {
+		return Hash{}, false
+	}
+	return h.Offset(offset.lat, offset.lon)
+}
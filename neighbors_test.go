@@ -0,0 +1,102 @@
+package geohash
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDirection_String(t *testing.T) {
+	assert.Equal(t, "North", North.String())
+	assert.Equal(t, "SouthWest", SouthWest.String())
+	assert.Equal(t, "NorthWest", NorthWest.String())
+	assert.Equal(t, "Unknown", Direction(8).String())
+}
+
+func TestHash_Neighbors(t *testing.T) {
+	h := mustParse("s0000")
+	neighbors, ok := h.Neighbors()
+	assert.Equal(t, [8]Hash{
+		North:     mustParse("s0002"),
+		NorthEast: mustParse("s0003"),
+		East:      mustParse("s0001"),
+		SouthEast: mustParse("kpbpc"),
+		South:     mustParse("kpbpb"),
+		SouthWest: mustParse("7zzzz"),
+		West:      mustParse("ebpbp"),
+		NorthWest: mustParse("ebpbr"),
+	}, neighbors)
+	assert.Equal(t, [8]bool{true, true, true, true, true, true, true, true}, ok)
+
+	for d := North; d <= NorthWest; d++ {
+		n, found := h.Neighbor(d)
+		assert.Equal(t, true, found)
+		assert.Equal(t, neighbors[d], n, d.String())
+	}
+
+	// at the north pole and across the antimeridian
+	h = mustParse("zzz")
+	neighbors, ok = h.Neighbors()
+	assert.Equal(t, [8]Hash{
+		East:      mustParse("bpb"),
+		SouthEast: mustParse("bp8"),
+		South:     mustParse("zzx"),
+		SouthWest: mustParse("zzw"),
+		West:      mustParse("zzy"),
+	}, neighbors)
+	assert.Equal(t, [8]bool{
+		East:      true,
+		SouthEast: true,
+		South:     true,
+		SouthWest: true,
+		West:      true,
+	}, ok)
+
+	for d := North; d <= NorthWest; d++ {
+		n, found := h.Neighbor(d)
+		assert.Equal(t, ok[d], found, d.String())
+		assert.Equal(t, neighbors[d], n, d.String())
+	}
+
+	// Top() still returns the geohash across the pole
+	assert.Equal(t, mustParse("gzz"), h.Top())
+
+	_, found := h.Neighbor(Direction(-1))
+	assert.Equal(t, false, found)
+}
+
+func TestHash_Ring(t *testing.T) {
+	h := mustParse("s0000")
+	assert.Equal(t, []Hash{h}, h.Ring(0))
+	assert.Equal(t, []Hash(nil), h.Ring(-1))
+
+	assert.Equal(t, []Hash{
+		mustParse("s0001"), mustParse("s0003"), mustParse("s0002"), mustParse("ebpbr"),
+		mustParse("ebpbp"), mustParse("7zzzz"), mustParse("kpbpb"), mustParse("kpbpc"),
+	}, h.Ring(1))
+	assert.Equal(t, 16, len(h.Ring(2)))
+
+	// the top row is skipped at the north pole
+	h = mustParse("b")
+	assert.Equal(t, []Hash{
+		mustParse("c"), mustParse("z"), mustParse("x"), mustParse("8"), mustParse("9"),
+	}, h.Ring(1))
+
+	// the columns are not repeated when the ring is wider than the world
+	assert.Equal(t, []Hash{
+		mustParse("u"), mustParse("h"), mustParse("k"), mustParse("s"),
+	}, h.Ring(4))
+}
+
+func TestHash_Disk(t *testing.T) {
+	h := mustParse("s0000")
+	disk := h.Disk(2)
+	assert.Equal(t, 25, len(disk))
+	assert.Equal(t, 25, len(hashListToStrings(disk)))
+	assert.Equal(t, append(append(h.Ring(0), h.Ring(1)...), h.Ring(2)...), disk)
+
+	disk = mustParse("b").Disk(10)
+	assert.Equal(t, 32, len(disk))
+	assert.Equal(t, 32, len(hashListToStrings(disk)))
+
+	assert.Equal(t, []Hash(nil), h.Disk(-1))
+}
@@ -0,0 +1,156 @@
+package geohash
+
+import (
+	"math"
+)
+
+// Polygon is a planar polygon in lat/lon degrees with optional holes.
+// The rings do not need to be closed (the last point equals the first point).
+// Polygons crossing the antimeridian are not supported
+type Polygon struct {
+	Exterior []Pos
+	Holes    [][]Pos
+}
+
+// PolygonCovering is the result of CoverPolygon
+type PolygonCovering struct {
+	Interior []Hash // geohashes fully contained in the polygon
+	Boundary []Hash // geohashes intersecting the polygon edges
+}
+
+// Contains checks whether the position is inside the polygon and not inside any of its holes
+func (p Polygon) Contains(pos Pos) bool {
+	if !ringContains(p.Exterior, pos) {
+		return false
+	}
+	for _, hole := range p.Holes {
+		if ringContains(hole, pos) {
+			return false
+		}
+	}
+	return true
+}
+
+// CoverPolygon computes all geohashes whose Rec() intersects the polygon.
+// Positions inside the interior geohashes are always inside the polygon
+func CoverPolygon(polygon Polygon, precision uint32) PolygonCovering {
+	if len(polygon.Exterior) == 0 {
+		return PolygonCovering{}
+	}
+
+	var result PolygonCovering
+	for _, h := range CoverRectangle(polygon.bounds(), precision) {
+		box := h.Bounds()
+		if polygon.intersectsEdges(box) {
+			result.Boundary = append(result.Boundary, h)
+			continue
+		}
+
+		// no edge crosses the cell, so the cell is either fully inside or fully outside
+		if polygon.Contains(boundsCenter(box)) {
+			result.Interior = append(result.Interior, h)
+		}
+	}
+	return result
+}
+
+func (p Polygon) bounds() Bounds {
+	result := Bounds{
+		Min: Pos{Lat: math.MaxFloat64, Lon: math.MaxFloat64},
+		Max: Pos{Lat: -math.MaxFloat64, Lon: -math.MaxFloat64},
+	}
+	for _, pos := range p.Exterior {
+		result.Min.Lat = math.Min(result.Min.Lat, pos.Lat)
+		result.Min.Lon = math.Min(result.Min.Lon, pos.Lon)
+		result.Max.Lat = math.Max(result.Max.Lat, pos.Lat)
+		result.Max.Lon = math.Max(result.Max.Lon, pos.Lon)
+	}
+	return result
+}
+
+func (p Polygon) intersectsEdges(box Bounds) bool {
+	if ringIntersectsBox(p.Exterior, box) {
+		return true
+	}
+	for _, hole := range p.Holes {
+		if ringIntersectsBox(hole, box) {
+			return true
+		}
+	}
+	return false
+}
+
+func boundsCenter(b Bounds) Pos {
+	return Pos{
+		Lat: (b.Min.Lat + b.Max.Lat) / 2,
+		Lon: (b.Min.Lon + b.Max.Lon) / 2,
+	}
+}
+
+// ringContains uses the even-odd rule
+func ringContains(ring []Pos, pos Pos) bool {
+	inside := false
+
+	prev := len(ring) - 1
+	for i := range ring {
+		a := ring[i]
+		b := ring[prev]
+		prev = i
+
+		if (a.Lat > pos.Lat) == (b.Lat > pos.Lat) {
+			continue
+		}
+
+		lon := a.Lon + (b.Lon-a.Lon)*(pos.Lat-a.Lat)/(b.Lat-a.Lat)
+		if pos.Lon < lon {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func ringIntersectsBox(ring []Pos, box Bounds) bool {
+	prev := len(ring) - 1
+	for i := range ring {
+		if segmentIntersectsBox(ring[prev], ring[i], box) {
+			return true
+		}
+		prev = i
+	}
+	return false
+}
+
+// segmentIntersectsBox uses the Liang-Barsky clipping algorithm, the box is closed
+func segmentIntersectsBox(a, b Pos, box Bounds) bool {
+	lower := 0.0
+	upper := 1.0
+
+	clip := func(p float64, q float64) bool {
+		if p == 0 {
+			// parallel to the clipping edge
+			return q >= 0
+		}
+
+		r := q / p
+		if p < 0 {
+			if r > upper {
+				return false
+			}
+			lower = math.Max(lower, r)
+		} else {
+			if r < lower {
+				return false
+			}
+			upper = math.Min(upper, r)
+		}
+		return true
+	}
+
+	dLon := b.Lon - a.Lon
+	dLat := b.Lat - a.Lat
+
+	return clip(-dLon, a.Lon-box.Min.Lon) &&
+		clip(dLon, box.Max.Lon-a.Lon) &&
+		clip(-dLat, a.Lat-box.Min.Lat) &&
+		clip(dLat, box.Max.Lat-a.Lat)
+}
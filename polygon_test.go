@@ -0,0 +1,123 @@
+package geohash
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func squareRing(min, max float64) []Pos {
+	return []Pos{
+		{Lat: min, Lon: min},
+		{Lat: min, Lon: max},
+		{Lat: max, Lon: max},
+		{Lat: max, Lon: min},
+	}
+}
+
+func TestPolygon_Contains(t *testing.T) {
+	p := Polygon{
+		Exterior: squareRing(0, 10),
+		Holes: [][]Pos{
+			squareRing(4, 6),
+		},
+	}
+
+	assert.Equal(t, true, p.Contains(Pos{Lat: 1, Lon: 1}))
+	assert.Equal(t, true, p.Contains(Pos{Lat: 3, Lon: 7}))
+	assert.Equal(t, false, p.Contains(Pos{Lat: 5, Lon: 5}))
+	assert.Equal(t, false, p.Contains(Pos{Lat: 11, Lon: 5}))
+	assert.Equal(t, false, p.Contains(Pos{Lat: -1, Lon: 5}))
+
+	triangle := Polygon{
+		Exterior: []Pos{
+			{Lat: 0, Lon: 0},
+			{Lat: 0, Lon: 10},
+			{Lat: 10, Lon: 0},
+			{Lat: 0, Lon: 0},
+		},
+	}
+	assert.Equal(t, true, triangle.Contains(Pos{Lat: 4, Lon: 4}))
+	assert.Equal(t, false, triangle.Contains(Pos{Lat: 6, Lon: 6}))
+}
+
+func TestSegmentIntersectsBox(t *testing.T) {
+	box := Bounds{
+		Min: Pos{Lat: 0, Lon: 0},
+		Max: Pos{Lat: 1, Lon: 1},
+	}
+
+	assert.Equal(t, true, segmentIntersectsBox(Pos{Lat: 0.5, Lon: -1}, Pos{Lat: 0.5, Lon: 2}, box))
+	assert.Equal(t, true, segmentIntersectsBox(Pos{Lat: 0.2, Lon: 0.2}, Pos{Lat: 0.3, Lon: 0.3}, box))
+	assert.Equal(t, true, segmentIntersectsBox(Pos{Lat: -1, Lon: 0.5}, Pos{Lat: 0, Lon: 0.5}, box))
+	assert.Equal(t, true, segmentIntersectsBox(Pos{Lat: -1, Lon: 1}, Pos{Lat: 1, Lon: -1}, box))
+
+	assert.Equal(t, false, segmentIntersectsBox(Pos{Lat: -1, Lon: 0.5}, Pos{Lat: -0.1, Lon: 0.5}, box))
+	assert.Equal(t, false, segmentIntersectsBox(Pos{Lat: -1, Lon: 0.9}, Pos{Lat: 0.9, Lon: 2.5}, box))
+	assert.Equal(t, false, segmentIntersectsBox(Pos{Lat: 2, Lon: 2}, Pos{Lat: 3, Lon: 3}, box))
+}
+
+func TestCoverPolygon(t *testing.T) {
+	covering := CoverPolygon(Polygon{
+		Exterior: squareRing(0.1, 4.1),
+	}, 3)
+	assert.Equal(t, []Hash{mustParse("s03")}, covering.Interior)
+	assert.Equal(t, 8, len(covering.Boundary))
+
+	covering = CoverPolygon(Polygon{
+		Exterior: squareRing(0.1, 4.1),
+		Holes: [][]Pos{
+			squareRing(1.2, 3.0),
+		},
+	}, 3)
+	assert.Equal(t, []Hash(nil), covering.Interior)
+	assert.Equal(t, 8, len(covering.Boundary))
+
+	assert.Equal(t, PolygonCovering{}, CoverPolygon(Polygon{}, 3))
+}
+
+func TestCoverPolygon_Properties_Based_Testing(t *testing.T) {
+	seed := time.Now().Unix()
+	fmt.Println("SEED:", seed)
+	rand.Seed(seed)
+
+	for i := 0; i < 50; i++ {
+		lat := mathRand(-60, 60)
+		lon := mathRand(-170, 170)
+
+		var ring []Pos
+		for k := 0; k < randInt(3, 8); k++ {
+			ring = append(ring, Pos{
+				Lat: lat + mathRand(-1, 1),
+				Lon: lon + mathRand(-1, 1),
+			})
+		}
+		polygon := Polygon{Exterior: ring}
+
+		prec := uint32(randInt(3, 4))
+		covering := CoverPolygon(polygon, prec)
+
+		interior := hashListToStrings(covering.Interior)
+		boundary := hashListToStrings(covering.Boundary)
+
+		for k := 0; k < 2000; k++ {
+			p := Pos{
+				Lat: lat + mathRand(-1, 1),
+				Lon: lon + mathRand(-1, 1),
+			}
+			s := ComputeGeohash(p, prec).String()
+
+			_, isInterior := interior[s]
+			_, isBoundary := boundary[s]
+
+			if polygon.Contains(p) {
+				assert.True(t, isInterior || isBoundary, p)
+			}
+			if isInterior {
+				assert.True(t, polygon.Contains(p), p)
+			}
+		}
+	}
+}
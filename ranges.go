@@ -0,0 +1,151 @@
+package geohash
+
+import (
+	"sort"
+)
+
+// Range is a half-open interval [Start, End) of integer geohashes (see Hash.Uint64) with the same bit precision.
+// End = 0 means the range extends to the end of the key space
+type Range struct {
+	Start uint64
+	End   uint64
+	Bits  uint32
+}
+
+// ComputeRanges converts geohashes into a minimal sorted list of ranges,
+// cells that are adjacent in the Z-order curve are merged into a single range.
+// The ranges use the finest bit precision of the geohashes, a coarser geohash becomes the range
+// of all its sub cells at that precision. Precisions finer than 64 bits are truncated to 60 bits
+// if they are multiples of 5, so that StringRange stays defined, otherwise to 64 bits
+func ComputeRanges(hashes []Hash) []Range {
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	bitCount := uint32(0)
+	for _, h := range hashes {
+		if h.bits > bitCount {
+			bitCount = h.bits
+		}
+	}
+	if bitCount > 64 {
+		bitCount = maxRangeBits(bitCount)
+	}
+
+	type interval struct {
+		start uint64
+		last  uint64 // inclusive to avoid overflow
+	}
+
+	intervals := make([]interval, 0, len(hashes))
+	for _, h := range hashes {
+		if h.bits > bitCount {
+			h = h.truncate(bitCount)
+		}
+
+		shift := bitCount - h.bits
+		value, _ := h.Uint64()
+		start := value << shift
+		intervals = append(intervals, interval{
+			start: start,
+			last:  start | lowBitsMask(shift),
+		})
+	}
+
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i].start < intervals[j].start
+	})
+
+	result := make([]Range, 0, len(intervals))
+	current := intervals[0]
+	for _, next := range intervals[1:] {
+		if current.last == lowBitsMask(bitCount) || next.start <= current.last+1 {
+			if next.last > current.last {
+				current.last = next.last
+			}
+			continue
+		}
+
+		result = append(result, Range{Start: current.start, End: current.last + 1, Bits: bitCount})
+		current = next
+	}
+	result = append(result, Range{Start: current.start, End: current.last + 1, Bits: bitCount})
+
+	if bitCount < 64 && result[len(result)-1].End == uint64(1)<<bitCount {
+		result[len(result)-1].End = 0
+	}
+	return result
+}
+
+// maxRangeBits returns the bit precision of the ranges of geohashes finer than 64 bits
+func maxRangeBits(bitCount uint32) uint32 {
+	if bitCount%5 == 0 {
+		return 60
+	}
+	return 64
+}
+
+// lowBitsMask returns an integer with the lowest count bits set, support count <= 64
+func lowBitsMask(count uint32) uint64 {
+	if count == 0 {
+		return 0
+	}
+	return ^uint64(0) >> (64 - count)
+}
+
+// NearbyRanges computes the ranges of the geohashes returned by NearbyGeohashList, radius is in km
+func NearbyRanges(origin Pos, radius float64, precision uint32) []Range {
+	return ComputeRanges(NearbyGeohashList(origin, radius, precision))
+}
+
+// StringRange returns the range [start, end) of geohash strings, end = "" means no upper bound.
+// The range also contains all the longer geohash strings of the cells inside it.
+// Returns ErrNotCharacterAligned if Bits is not a multiple of 5
+func (r Range) StringRange() (start string, end string, err error) {
+	if r.Bits%5 != 0 {
+		return "", "", ErrNotCharacterAligned
+	}
+
+	startHash, err := FromUint64(r.Start, r.Bits)
+	if err != nil {
+		return "", "", err
+	}
+	start = startHash.String()
+
+	if r.End == 0 {
+		return start, "", nil
+	}
+
+	endHash, err := FromUint64(r.End, r.Bits)
+	if err != nil {
+		return "", "", err
+	}
+	return start, endHash.String(), nil
+}
+
+// WithBits converts the range to another bit precision, support bitPrecision in [1, 64].
+// Useful for keys stored at a fixed bit precision, e.g. 52 bits of Redis GEO.
+// Converting to a coarser precision returns the smallest range containing this range
+func (r Range) WithBits(bitPrecision uint32) Range {
+	if bitPrecision >= r.Bits {
+		shift := bitPrecision - r.Bits
+		return Range{
+			Start: r.Start << shift,
+			End:   r.End << shift,
+			Bits:  bitPrecision,
+		}
+	}
+
+	shift := r.Bits - bitPrecision
+	result := Range{
+		Start: r.Start >> shift,
+		Bits:  bitPrecision,
+	}
+	if r.End != 0 {
+		result.End = (r.End-1)>>shift + 1
+		if result.End == uint64(1)<<bitPrecision {
+			result.End = 0
+		}
+	}
+	return result
+}
@@ -0,0 +1,207 @@
+package geohash
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func mustParse(s string) Hash {
+	h, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return h
+}
+
+func mustUint64(h Hash) uint64 {
+	value, err := h.Uint64()
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+func TestComputeRanges(t *testing.T) {
+	assert.Equal(t, []Range(nil), ComputeRanges(nil))
+
+	ranges := ComputeRanges([]Hash{
+		mustParse("s03"),
+		mustParse("s01"),
+		mustParse("s00"),
+		mustParse("s05"),
+		mustParse("s02"),
+	})
+	start := mustUint64(mustParse("s00"))
+	assert.Equal(t, []Range{
+		{Start: start, End: start + 4, Bits: 15},
+		{Start: start + 5, End: start + 6, Bits: 15},
+	}, ranges)
+
+	startStr, endStr, err := ranges[0].StringRange()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "s00", startStr)
+	assert.Equal(t, "s04", endStr)
+
+	startStr, endStr, err = ranges[1].StringRange()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "s05", startStr)
+	assert.Equal(t, "s06", endStr)
+}
+
+func TestComputeRanges_Mixed_Precision(t *testing.T) {
+	ranges := ComputeRanges([]Hash{
+		mustParse("s0"),
+		mustParse("s1z"),
+		mustParse("s10"),
+	})
+	assert.Equal(t, []Range{
+		{Start: mustUint64(mustParse("s00")), End: mustUint64(mustParse("s11")), Bits: 15},
+		{Start: mustUint64(mustParse("s1z")), End: mustUint64(mustParse("s20")), Bits: 15},
+	}, ranges)
+}
+
+func TestComputeRanges_End_Of_Key_Space(t *testing.T) {
+	ranges := ComputeRanges([]Hash{
+		mustParse("zy"),
+		mustParse("zz"),
+		mustParse("00"),
+	})
+	assert.Equal(t, []Range{
+		{Start: 0, End: 1, Bits: 10},
+		{Start: 1022, End: 0, Bits: 10},
+	}, ranges)
+
+	startStr, endStr, err := ranges[1].StringRange()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "zy", startStr)
+	assert.Equal(t, "", endStr)
+
+	h := ComputeGeohashBits(Pos{Lat: 90, Lon: 180}, 64)
+	assert.Equal(t, []Range{
+		{Start: mustUint64(h), End: 0, Bits: 64},
+	}, ComputeRanges([]Hash{h}))
+}
+
+func TestComputeRanges_Finer_Than_64_Bits(t *testing.T) {
+	h := mustParse("u2xuyesss0kf2j7jhn6z")
+	ranges := ComputeRanges([]Hash{h})
+
+	start := mustUint64(mustParse("u2xuyesss0kf"))
+	assert.Equal(t, []Range{
+		{Start: start, End: start + 1, Bits: 60},
+	}, ranges)
+
+	startStr, endStr, err := ranges[0].StringRange()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "u2xuyesss0kf", startStr)
+	assert.Equal(t, "u2xuyesss0kg", endStr)
+
+	h = ComputeGeohashBits(Pos{Lat: 10.7769, Lon: 106.7009}, 66)
+	assert.Equal(t, []Range{
+		{Start: mustUint64(h.truncate(64)), End: mustUint64(h.truncate(64)) + 1, Bits: 64},
+	}, ComputeRanges([]Hash{h}))
+}
+
+func TestRange_StringRange_Not_Character_Aligned(t *testing.T) {
+	h := ComputeGeohashBits(Pos{Lat: 10.7769, Lon: 106.7009}, 52)
+	ranges := ComputeRanges([]Hash{h})
+
+	startStr, endStr, err := ranges[0].StringRange()
+	assert.Equal(t, ErrNotCharacterAligned, err)
+	assert.Equal(t, "", startStr)
+	assert.Equal(t, "", endStr)
+
+	startStr, endStr, err = ranges[0].WithBits(50).StringRange()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, h.truncate(50).String(), startStr)
+	assert.Equal(t, 10, len(endStr))
+}
+
+func TestRange_WithBits(t *testing.T) {
+	r := Range{Start: 3, End: 5, Bits: 10}
+	assert.Equal(t, Range{Start: 3 << 42, End: 5 << 42, Bits: 52}, r.WithBits(52))
+	assert.Equal(t, Range{Start: 1, End: 3, Bits: 9}, r.WithBits(9))
+	assert.Equal(t, Range{Start: 0, End: 2, Bits: 8}, r.WithBits(8))
+
+	r = Range{Start: 1022, End: 0, Bits: 10}
+	assert.Equal(t, Range{Start: 1022 << 54, End: 0, Bits: 64}, r.WithBits(64))
+	assert.Equal(t, Range{Start: 1, End: 0, Bits: 1}, r.WithBits(1))
+
+	r = Range{Start: 1020, End: 1023, Bits: 10}
+	assert.Equal(t, Range{Start: 255, End: 0, Bits: 8}, r.WithBits(8))
+}
+
+func TestNearbyRanges(t *testing.T) {
+	origin := Pos{
+		Lat: 0.7,
+		Lon: 0.7,
+	}
+
+	ranges := NearbyRanges(origin, 20, 3)
+	start := mustUint64(mustParse("s00"))
+	assert.Equal(t, []Range{
+		{Start: start, End: start + 1, Bits: 15},
+	}, ranges)
+
+	// s00, s01 (right) and s02 (top) are adjacent in the Z-order curve
+	ranges = NearbyRanges(origin, 80, 3)
+	assert.Equal(t, []Range{
+		{Start: mustUint64(mustParse("ebp")), End: mustUint64(mustParse("ebq")), Bits: 15},
+		{Start: mustUint64(mustParse("kpb")), End: mustUint64(mustParse("kpc")), Bits: 15},
+		{Start: start, End: start + 3, Bits: 15},
+	}, ranges)
+}
+
+func TestNearbyRanges_Properties_Based_Testing(t *testing.T) {
+	seed := time.Now().Unix()
+	fmt.Println("SEED:", seed)
+	rand.Seed(seed)
+
+	for i := 0; i < 100; i++ {
+		origin := Pos{
+			Lat: mathRand(-60, 60),
+			Lon: mathRand(-170, 170),
+		}
+		radius := mathRand(1, 30)
+		prec := uint32(randInt(3, 6))
+
+		hashes := NearbyGeohashList(origin, radius, prec)
+		ranges := NearbyRanges(origin, radius, prec)
+
+		total := uint64(0)
+		for i, r := range ranges {
+			assert.Less(t, r.Start, r.End)
+			total += r.End - r.Start
+
+			if i > 0 {
+				assert.Less(t, ranges[i-1].End, r.Start)
+			}
+		}
+		assert.Equal(t, uint64(len(hashes)), total)
+
+		for _, h := range hashes {
+			v := mustUint64(h)
+			found := false
+			for _, r := range ranges {
+				if r.Start <= v && v < r.End {
+					found = true
+				}
+			}
+			assert.True(t, found)
+
+			s := h.String()
+			found = false
+			for _, r := range ranges {
+				startStr, endStr, err := r.StringRange()
+				assert.Equal(t, nil, err)
+				if startStr <= s && s < endStr {
+					found = true
+				}
+			}
+			assert.True(t, found)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package geohash
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"math/bits"
+	"strconv"
+)
+
+// MaxPackedBitPrecision is the maximum bit precision of a geohash packed into an int64 by Hash.Int64
+const MaxPackedBitPrecision = 62
+
+var _ sql.Scanner = &Hash{}
+var _ driver.Valuer = Hash{}
+var _ sql.Scanner = &NullHash{}
+var _ driver.Valuer = NullHash{}
+var _ sql.Scanner = &Int64Hash{}
+var _ driver.Valuer = Int64Hash{}
+var _ sql.Scanner = &NullInt64Hash{}
+var _ driver.Valuer = NullInt64Hash{}
+
+// Int64 packs the bit precision and the interleaved bits into a positive integer:
+// a marker bit 1 followed by the interleaved bits, so geohashes of different precisions never collide.
+// Returns ErrInvalidPrecision if the bit precision is zero or greater than MaxPackedBitPrecision
+func (h Hash) Int64() (int64, error) {
+	if h.bits == 0 || h.bits > MaxPackedBitPrecision {
+		return 0, ErrInvalidPrecision
+	}
+	value, _ := h.Uint64()
+	return int64(uint64(1)<<h.bits | value), nil
+}
+
+// FromInt64 is the inverse of Hash.Int64(), returns ErrValueOutOfRange if the value is not positive
+// and ErrInvalidPrecision if the value is only the marker bit
+func FromInt64(value int64) (Hash, error) {
+	if value <= 0 {
+		return Hash{}, ErrValueOutOfRange
+	}
+
+	bitPrecision := uint32(bits.Len64(uint64(value)) - 1)
+	if bitPrecision == 0 {
+		return Hash{}, ErrInvalidPrecision
+	}
+	return FromUint64(uint64(value)&lowBitsMask(bitPrecision), bitPrecision)
+}
+
+// Value stores the geohash as its string, use Int64Hash for integer columns.
+// Returns ErrNotCharacterAligned if the number of bits is not a multiple of 5
+func (h Hash) Value() (driver.Value, error) {
+	text, err := h.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return string(text), nil
+}
+
+// Scan reads a geohash from a string column, use Int64Hash for integer columns
+// and NullHash for nullable columns
+func (h *Hash) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case string:
+		return h.UnmarshalText([]byte(value))
+
+	case []byte:
+		return h.UnmarshalText(value)
+
+	default:
+		return fmt.Errorf("geohash: cannot scan %T into Hash", src)
+	}
+}
+
+// NullHash is a geohash that may be null, the same as sql.NullString
+type NullHash struct {
+	Hash  Hash
+	Valid bool // Valid is true if Hash is not NULL
+}
+
+// Value returns nil if the geohash is not valid, otherwise the same as Hash.Value
+func (n NullHash) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Hash.Value()
+}
+
+// Scan sets Valid to false for NULL, otherwise the same as Hash.Scan
+func (n *NullHash) Scan(src interface{}) error {
+	if src == nil {
+		*n = NullHash{}
+		return nil
+	}
+
+	if err := n.Hash.Scan(src); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// Int64Hash is a geohash stored in an integer column, packed by Hash.Int64().
+// Unlike the string encoding, it supports bit precisions that are not multiples of 5
+type Int64Hash Hash
+
+// Value stores the geohash packed by Hash.Int64()
+func (h Int64Hash) Value() (driver.Value, error) {
+	value, err := Hash(h).Int64()
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// Scan reads a geohash packed by Hash.Int64() from an integer, or from its decimal string
+// returned by the text protocols of some drivers. Use NullInt64Hash for nullable columns
+func (h *Int64Hash) Scan(src interface{}) error {
+	var value int64
+	switch v := src.(type) {
+	case int64:
+		value = v
+
+	case []byte:
+		return h.Scan(string(v))
+
+	case string:
+		parsed, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("geohash: cannot scan %q into Int64Hash: %w", v, err)
+		}
+		value = parsed
+
+	default:
+		return fmt.Errorf("geohash: cannot scan %T into Int64Hash", src)
+	}
+
+	result, err := FromInt64(value)
+	if err != nil {
+		return err
+	}
+	*h = Int64Hash(result)
+	return nil
+}
+
+// NullInt64Hash is an Int64Hash that may be null, the same as sql.NullInt64
+type NullInt64Hash struct {
+	Hash  Int64Hash
+	Valid bool // Valid is true if Hash is not NULL
+}
+
+// Value returns nil if the geohash is not valid, otherwise the same as Int64Hash.Value
+func (n NullInt64Hash) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Hash.Value()
+}
+
+// Scan sets Valid to false for NULL, otherwise the same as Int64Hash.Scan
+func (n *NullInt64Hash) Scan(src interface{}) error {
+	if src == nil {
+		*n = NullInt64Hash{}
+		return nil
+	}
+
+	if err := n.Hash.Scan(src); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
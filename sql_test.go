@@ -0,0 +1,269 @@
+package geohash
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"testing"
+)
+
+// fakeDriver stores the arguments of Exec and returns the stored values of the last Exec in Query
+type fakeDriver struct {
+	values []driver.Value
+}
+
+type fakeConn struct {
+	driver *fakeDriver
+}
+
+type fakeStmt struct {
+	driver *fakeDriver
+}
+
+type fakeRows struct {
+	values []driver.Value
+}
+
+var testFakeDriver = &fakeDriver{}
+
+func init() {
+	sql.Register("geohash_fake", testFakeDriver)
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) {
+	return &fakeConn{driver: d}, nil
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return &fakeStmt{driver: c.driver}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+func (s *fakeStmt) Close() error {
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.driver.values = args
+	return driver.RowsAffected(len(args)), nil
+}
+
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &fakeRows{values: s.driver.values}, nil
+}
+
+func (r *fakeRows) Columns() []string {
+	return []string{"value"}
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	dest[0] = r.values[0]
+	r.values = r.values[1:]
+	return nil
+}
+
+func openFakeDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("geohash_fake", "")
+	assert.Equal(t, nil, err)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+	return db
+}
+
+func TestHash_Int64(t *testing.T) {
+	_, err := Hash{}.Int64()
+	assert.Equal(t, ErrInvalidPrecision, err)
+
+	value, err := mustParse("s").Int64()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(0b1_11000), value)
+
+	h := ComputeGeohashBits(Pos{Lat: 10.7769, Lon: 106.7009}, 62)
+	value, err = h.Int64()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1)<<62|int64(mustUint64(h)), value)
+
+	_, err = ComputeGeohashBits(Pos{}, 63).Int64()
+	assert.Equal(t, ErrInvalidPrecision, err)
+
+	for _, bitCount := range []uint32{1, 5, 13, 40, 61, 62} {
+		h := ComputeGeohashBits(Pos{Lat: 10.7769, Lon: 106.7009}, bitCount)
+		value, err := h.Int64()
+		assert.Equal(t, nil, err)
+
+		result, err := FromInt64(value)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, h, result)
+	}
+
+	_, err = FromInt64(1)
+	assert.Equal(t, ErrInvalidPrecision, err)
+	_, err = FromInt64(0)
+	assert.Equal(t, ErrValueOutOfRange, err)
+	_, err = FromInt64(-5)
+	assert.Equal(t, ErrValueOutOfRange, err)
+}
+
+func TestHash_Scan(t *testing.T) {
+	var h Hash
+	assert.Equal(t, nil, h.Scan("w3gv2"))
+	assert.Equal(t, mustParse("w3gv2"), h)
+
+	assert.Equal(t, nil, h.Scan([]byte("u")))
+	assert.Equal(t, mustParse("u"), h)
+
+	assert.Equal(t, nil, h.Scan([]byte("s")))
+	assert.Equal(t, mustParse("s"), h)
+
+	assert.Equal(t, &InvalidCharacterError{Char: 'a', Index: 0}, h.Scan("a"))
+	assert.Equal(t, "geohash: cannot scan int64 into Hash", h.Scan(int64(0b1_11000)).Error())
+	assert.Equal(t, "geohash: cannot scan <nil> into Hash", h.Scan(nil).Error())
+	assert.Equal(t, "geohash: cannot scan float64 into Hash", h.Scan(1.5).Error())
+	assert.Equal(t, mustParse("s"), h)
+
+	value, err := mustParse("w3gv2").Value()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "w3gv2", value)
+
+	_, err = ComputeGeohashBits(Pos{}, 13).Value()
+	assert.Equal(t, ErrNotCharacterAligned, err)
+}
+
+func TestNullHash(t *testing.T) {
+	n := NullHash{Hash: mustParse("s"), Valid: true}
+	assert.Equal(t, nil, n.Scan(nil))
+	assert.Equal(t, NullHash{}, n)
+
+	value, err := n.Value()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nil, value)
+
+	assert.Equal(t, nil, n.Scan("w3"))
+	assert.Equal(t, NullHash{Hash: mustParse("w3"), Valid: true}, n)
+
+	value, err = n.Value()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "w3", value)
+
+	assert.Error(t, n.Scan(true))
+}
+
+func TestInt64Hash(t *testing.T) {
+	var h Int64Hash
+	assert.Equal(t, nil, h.Scan(int64(0b1_11000)))
+	assert.Equal(t, mustParse("s"), Hash(h))
+
+	// the text protocol of MySQL returns integers as decimal strings
+	assert.Equal(t, nil, h.Scan([]byte("3")))
+	assert.Equal(t, ComputeGeohashBits(Pos{Lat: 0, Lon: 0}, 1), Hash(h))
+
+	assert.Equal(t, nil, h.Scan("56"))
+	assert.Equal(t, mustParse("s"), Hash(h))
+
+	assert.Equal(t, ErrValueOutOfRange, h.Scan(int64(0)))
+	assert.Error(t, h.Scan([]byte("w3gv2")))
+	assert.Error(t, h.Scan("1.5"))
+	assert.Equal(t, "geohash: cannot scan <nil> into Int64Hash", h.Scan(nil).Error())
+	assert.Equal(t, "geohash: cannot scan float64 into Int64Hash", h.Scan(1.5).Error())
+	assert.Equal(t, mustParse("s"), Hash(h))
+
+	// bit precisions that are not multiples of 5
+	redis := ComputeGeohashBits(Pos{Lat: 10.7769, Lon: 106.7009}, 52)
+	value, err := Int64Hash(redis).Value()
+	assert.Equal(t, nil, err)
+	packed, _ := redis.Int64()
+	assert.Equal(t, packed, value)
+
+	assert.Equal(t, nil, h.Scan(value))
+	assert.Equal(t, redis, Hash(h))
+
+	_, err = Int64Hash(ComputeGeohashBits(Pos{}, 63)).Value()
+	assert.Equal(t, ErrInvalidPrecision, err)
+}
+
+func TestNullInt64Hash(t *testing.T) {
+	n := NullInt64Hash{Hash: Int64Hash(mustParse("s")), Valid: true}
+	assert.Equal(t, nil, n.Scan(nil))
+	assert.Equal(t, NullInt64Hash{}, n)
+
+	value, err := n.Value()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nil, value)
+
+	assert.Equal(t, nil, n.Scan(int64(0b1_11000)))
+	assert.Equal(t, NullInt64Hash{Hash: Int64Hash(mustParse("s")), Valid: true}, n)
+
+	value, err = n.Value()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(0b1_11000), value)
+
+	assert.Error(t, n.Scan(true))
+}
+
+func TestHash_SQL_Fake_Driver(t *testing.T) {
+	db := openFakeDB(t)
+
+	_, err := db.Exec("INSERT", mustParse("w3gv2"), NullHash{}, NullHash{Hash: mustParse("u"), Valid: true})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []driver.Value{"w3gv2", nil, "u"}, testFakeDriver.values)
+
+	rows, err := db.Query("SELECT")
+	assert.Equal(t, nil, err)
+
+	var hashes []NullHash
+	for rows.Next() {
+		var n NullHash
+		assert.Equal(t, nil, rows.Scan(&n))
+		hashes = append(hashes, n)
+	}
+	assert.Equal(t, nil, rows.Err())
+	assert.Equal(t, []NullHash{
+		{Hash: mustParse("w3gv2"), Valid: true},
+		{},
+		{Hash: mustParse("u"), Valid: true},
+	}, hashes)
+
+	// integer columns
+	redis := ComputeGeohashBits(Pos{Lat: 10.7769, Lon: 106.7009}, 52)
+	_, err = db.Exec("INSERT", Int64Hash(redis), NullInt64Hash{})
+	assert.Equal(t, nil, err)
+
+	rows, err = db.Query("SELECT")
+	assert.Equal(t, nil, err)
+
+	var integers []NullInt64Hash
+	for rows.Next() {
+		var n NullInt64Hash
+		assert.Equal(t, nil, rows.Scan(&n))
+		integers = append(integers, n)
+	}
+	assert.Equal(t, nil, rows.Err())
+	assert.Equal(t, []NullInt64Hash{
+		{Hash: Int64Hash(redis), Valid: true},
+		{},
+	}, integers)
+
+	_, err = db.Exec("INSERT", ComputeGeohashBits(Pos{}, 13))
+	assert.True(t, errors.Is(err, ErrNotCharacterAligned))
+}
@@ -0,0 +1,220 @@
+package geohash
+
+import (
+	"container/heap"
+	"math"
+)
+
+// Aggregate is the summary of the values of the points inside a geohash
+type Aggregate struct {
+	Count int
+	Sum   float64
+	Min   float64
+	Max   float64
+}
+
+// HashAggregate is a geohash with the aggregate of its points
+type HashAggregate struct {
+	Hash Hash
+	Aggregate
+}
+
+// CountTrie is a binary trie on the interleaved bits of geohashes, each node keeps the aggregate
+// of all points inside its geohash. Prefix counts are answered in O(bits of the prefix).
+// It is not safe for concurrent use
+type CountTrie struct {
+	root trieNode
+}
+
+type trieNode struct {
+	children [2]*trieNode
+	agg      Aggregate
+
+	// values of the points inserted at exactly this node, used to recompute Min and Max on delete
+	values []float64
+}
+
+// NewCountTrie creates an empty trie
+func NewCountTrie() *CountTrie {
+	return &CountTrie{}
+}
+
+// Insert adds a point with the value at the geohash
+func (t *CountTrie) Insert(h Hash, value float64) {
+	node := &t.root
+	node.add(value)
+
+	for index := uint32(0); index < h.bits; index++ {
+		bit := h.bitAt(index)
+		if node.children[bit] == nil {
+			node.children[bit] = &trieNode{}
+		}
+		node = node.children[bit]
+		node.add(value)
+	}
+	node.values = append(node.values, value)
+}
+
+// Delete removes a point with the value at the geohash, returns false if there is no such point
+func (t *CountTrie) Delete(h Hash, value float64) bool {
+	path := make([]*trieNode, 0, h.bits+1)
+
+	node := &t.root
+	path = append(path, node)
+	for index := uint32(0); index < h.bits; index++ {
+		node = node.children[h.bitAt(index)]
+		if node == nil {
+			return false
+		}
+		path = append(path, node)
+	}
+
+	found := -1
+	for i, v := range node.values {
+		if v == value {
+			found = i
+			break
+		}
+	}
+	if found < 0 {
+		return false
+	}
+
+	last := len(node.values) - 1
+	node.values[found] = node.values[last]
+	node.values = node.values[:last]
+
+	for i := len(path) - 1; i >= 0; i-- {
+		path[i].recompute()
+
+		// remove the empty child
+		if i > 0 && path[i].agg.Count == 0 {
+			parent := path[i-1]
+			for bit, child := range parent.children {
+				if child == path[i] {
+					parent.children[bit] = nil
+				}
+			}
+		}
+	}
+	return true
+}
+
+// Count returns the number of points inside the geohash
+func (t *CountTrie) Count(prefix Hash) int {
+	return t.Aggregate(prefix).Count
+}
+
+// Aggregate returns the aggregate of the points inside the geohash, the zero value if there is no point
+func (t *CountTrie) Aggregate(prefix Hash) Aggregate {
+	node := &t.root
+	for index := uint32(0); index < prefix.bits; index++ {
+		node = node.children[prefix.bitAt(index)]
+		if node == nil {
+			return Aggregate{}
+		}
+	}
+	return node.agg
+}
+
+// Densest returns at most n non-empty geohashes at the precision (in characters) with the most points,
+// sorted by the count descending then by the geohash strings.
+// Points inserted at a coarser precision are not counted
+func (t *CountTrie) Densest(precision uint32, n int) []HashAggregate {
+	if n <= 0 || t.root.agg.Count == 0 {
+		return nil
+	}
+	bitCount := precision * 5
+
+	// the count of a node is not less than the count of any of its descendants,
+	// so the geohashes at the precision are popped in the order of their counts
+	queue := &trieQueue{{node: &t.root}}
+
+	var result []HashAggregate
+	for queue.Len() > 0 && len(result) < n {
+		entry := heap.Pop(queue).(trieEntry)
+		if entry.hash.bits == bitCount {
+			result = append(result, HashAggregate{
+				Hash:      entry.hash,
+				Aggregate: entry.node.agg,
+			})
+			continue
+		}
+
+		for bit, child := range entry.node.children {
+			if child == nil {
+				continue
+			}
+			heap.Push(queue, trieEntry{
+				node: child,
+				hash: entry.hash.appendBits(uint64(bit), 1),
+			})
+		}
+	}
+	return result
+}
+
+func (n *trieNode) add(value float64) {
+	if n.agg.Count == 0 {
+		n.agg = Aggregate{Min: value, Max: value}
+	}
+	n.agg.Count++
+	n.agg.Sum += value
+	n.agg.Min = math.Min(n.agg.Min, value)
+	n.agg.Max = math.Max(n.agg.Max, value)
+}
+
+func (n *trieNode) recompute() {
+	n.agg = Aggregate{}
+	for _, v := range n.values {
+		n.add(v)
+	}
+
+	for _, child := range n.children {
+		if child == nil || child.agg.Count == 0 {
+			continue
+		}
+		if n.agg.Count == 0 {
+			n.agg = child.agg
+			continue
+		}
+		n.agg.Count += child.agg.Count
+		n.agg.Sum += child.agg.Sum
+		n.agg.Min = math.Min(n.agg.Min, child.agg.Min)
+		n.agg.Max = math.Max(n.agg.Max, child.agg.Max)
+	}
+}
+
+type trieEntry struct {
+	node *trieNode
+	hash Hash
+}
+
+// trieQueue is a max heap of the counts, ties are ordered by the geohash strings
+type trieQueue []trieEntry
+
+func (q trieQueue) Len() int {
+	return len(q)
+}
+
+func (q trieQueue) Less(i, j int) bool {
+	if q[i].node.agg.Count != q[j].node.agg.Count {
+		return q[i].node.agg.Count > q[j].node.agg.Count
+	}
+	return lessHash(q[i].hash, q[j].hash)
+}
+
+func (q trieQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *trieQueue) Push(x interface{}) {
+	*q = append(*q, x.(trieEntry))
+}
+
+func (q *trieQueue) Pop() interface{} {
+	old := *q
+	last := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return last
+}
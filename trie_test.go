@@ -0,0 +1,135 @@
+package geohash
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestCountTrie(t *testing.T) {
+	trie := NewCountTrie()
+	assert.Equal(t, 0, trie.Count(Hash{}))
+	assert.Equal(t, []HashAggregate(nil), trie.Densest(3, 10))
+
+	trie.Insert(mustParse("w3gv2"), 1)
+	trie.Insert(mustParse("w3gv3"), 5)
+	trie.Insert(mustParse("w3gu0"), -2)
+	trie.Insert(mustParse("w3gv2"), 4)
+	trie.Insert(mustParse("s0000"), 10)
+
+	assert.Equal(t, 5, trie.Count(Hash{}))
+	assert.Equal(t, 4, trie.Count(mustParse("w")))
+	assert.Equal(t, 3, trie.Count(mustParse("w3gv")))
+	assert.Equal(t, 2, trie.Count(mustParse("w3gv2")))
+	assert.Equal(t, 0, trie.Count(mustParse("w3gv2x")))
+	assert.Equal(t, 0, trie.Count(mustParse("u")))
+
+	// a prefix that is not a multiple of 5 bits
+	assert.Equal(t, 4, trie.Count(mustParse("w").truncate(3)))
+	assert.Equal(t, 5, trie.Count(mustParse("w").truncate(2)))
+
+	assert.Equal(t, Aggregate{Count: 4, Sum: 8, Min: -2, Max: 5}, trie.Aggregate(mustParse("w3g")))
+	assert.Equal(t, Aggregate{Count: 5, Sum: 18, Min: -2, Max: 10}, trie.Aggregate(Hash{}))
+	assert.Equal(t, Aggregate{}, trie.Aggregate(mustParse("z")))
+
+	assert.Equal(t, false, trie.Delete(mustParse("w3gv2"), 3))
+	assert.Equal(t, false, trie.Delete(mustParse("w3gv4"), 1))
+	assert.Equal(t, false, trie.Delete(mustParse("w3gv"), 1))
+
+	assert.Equal(t, true, trie.Delete(mustParse("w3gv3"), 5))
+	assert.Equal(t, Aggregate{Count: 3, Sum: 3, Min: -2, Max: 4}, trie.Aggregate(mustParse("w3g")))
+	assert.Equal(t, Aggregate{}, trie.Aggregate(mustParse("w3gv3")))
+
+	assert.Equal(t, true, trie.Delete(mustParse("w3gu0"), -2))
+	assert.Equal(t, Aggregate{Count: 2, Sum: 5, Min: 1, Max: 4}, trie.Aggregate(mustParse("w3g")))
+	assert.Equal(t, true, trie.Delete(mustParse("w3gv2"), 1))
+	assert.Equal(t, true, trie.Delete(mustParse("w3gv2"), 4))
+	assert.Equal(t, true, trie.Delete(mustParse("s0000"), 10))
+
+	assert.Equal(t, Aggregate{}, trie.Aggregate(Hash{}))
+	assert.Equal(t, [2]*trieNode{}, trie.root.children)
+}
+
+func TestCountTrie_Densest(t *testing.T) {
+	trie := NewCountTrie()
+	trie.Insert(mustParse("w3gv2"), 1)
+	trie.Insert(mustParse("w3gv3"), 2)
+	trie.Insert(mustParse("w3gu0"), 3)
+	trie.Insert(mustParse("s0000"), 4)
+	trie.Insert(mustParse("s0001"), 5)
+	trie.Insert(mustParse("u"), 6)
+
+	assert.Equal(t, []HashAggregate{
+		{Hash: mustParse("s000"), Aggregate: Aggregate{Count: 2, Sum: 9, Min: 4, Max: 5}},
+		{Hash: mustParse("w3gv"), Aggregate: Aggregate{Count: 2, Sum: 3, Min: 1, Max: 2}},
+	}, trie.Densest(4, 2))
+
+	assert.Equal(t, []HashAggregate{
+		{Hash: mustParse("w3g"), Aggregate: Aggregate{Count: 3, Sum: 6, Min: 1, Max: 3}},
+		{Hash: mustParse("s00"), Aggregate: Aggregate{Count: 2, Sum: 9, Min: 4, Max: 5}},
+	}, trie.Densest(3, 10))
+
+	// ties are ordered by the geohash strings
+	assert.Equal(t, []HashAggregate{
+		{Hash: mustParse("s0000"), Aggregate: Aggregate{Count: 1, Sum: 4, Min: 4, Max: 4}},
+		{Hash: mustParse("s0001"), Aggregate: Aggregate{Count: 1, Sum: 5, Min: 5, Max: 5}},
+		{Hash: mustParse("w3gu0"), Aggregate: Aggregate{Count: 1, Sum: 3, Min: 3, Max: 3}},
+	}, trie.Densest(5, 3))
+
+	assert.Equal(t, []HashAggregate(nil), trie.Densest(6, 3))
+	assert.Equal(t, []HashAggregate(nil), trie.Densest(5, 0))
+}
+
+func TestCountTrie_Properties_Based_Testing(t *testing.T) {
+	seed := time.Now().Unix()
+	fmt.Println("SEED:", seed)
+	rand.Seed(seed)
+
+	trie := NewCountTrie()
+	counts := map[string]int{}
+
+	var hashes []Hash
+	for i := 0; i < 5000; i++ {
+		h := ComputeGeohash(Pos{Lat: mathRand(0, 10), Lon: mathRand(100, 110)}, 4)
+		hashes = append(hashes, h)
+		trie.Insert(h, float64(i))
+	}
+	for i, h := range hashes {
+		if i%3 == 0 {
+			assert.Equal(t, true, trie.Delete(h, float64(i)))
+			continue
+		}
+		counts[h.String()[:3]]++
+	}
+
+	assert.Equal(t, len(hashes)-len(hashes)/3-1, trie.Count(Hash{}))
+
+	for prefix, count := range counts {
+		assert.Equal(t, count, trie.Count(mustParse(prefix)))
+	}
+
+	type prefixCount struct {
+		prefix string
+		count  int
+	}
+	var expected []prefixCount
+	for prefix, count := range counts {
+		expected = append(expected, prefixCount{prefix: prefix, count: count})
+	}
+	sort.Slice(expected, func(i, j int) bool {
+		if expected[i].count != expected[j].count {
+			return expected[i].count > expected[j].count
+		}
+		return expected[i].prefix < expected[j].prefix
+	})
+
+	densest := trie.Densest(3, 20)
+	assert.Equal(t, 20, len(densest))
+	for i, d := range densest {
+		assert.Equal(t, expected[i].prefix, d.Hash.String())
+		assert.Equal(t, expected[i].count, d.Count)
+	}
+}
@@ -0,0 +1,422 @@
+package geohash
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidWKT is returned when parsing a malformed or unsupported WKT string
+	ErrInvalidWKT = errors.New("geohash: invalid WKT")
+
+	// ErrInvalidWKB is returned when parsing a malformed or unsupported WKB
+	ErrInvalidWKB = errors.New("geohash: invalid WKB")
+)
+
+const (
+	wkbBigEndian    = 0
+	wkbLittleEndian = 1
+
+	wkbPoint   = 1
+	wkbPolygon = 3
+
+	// wkbSRIDFlag is set in the geometry type of an EWKB with an SRID, e.g. returned by PostGIS
+	wkbSRIDFlag = 0x20000000
+
+	wkbHeaderSize     = 1 + 4
+	wkbSRIDSize       = 4
+	wkbCoordinateSize = 16
+)
+
+// MultiPolygon is a set of polygons parsed from a POLYGON or MULTIPOLYGON WKT, it can be covered by CoverRegion
+type MultiPolygon []Polygon
+
+var _ Region = MultiPolygon{}
+
+// Contains checks whether the position is inside any of the polygons
+func (m MultiPolygon) Contains(pos Pos) bool {
+	for _, p := range m {
+		if p.Contains(pos) {
+			return true
+		}
+	}
+	return false
+}
+
+// RectBound returns the bounding box of all the polygons
+func (m MultiPolygon) RectBound() Bounds {
+	result := Bounds{
+		Min: Pos{Lat: math.MaxFloat64, Lon: math.MaxFloat64},
+		Max: Pos{Lat: -math.MaxFloat64, Lon: -math.MaxFloat64},
+	}
+	for _, p := range m {
+		b := p.bounds()
+		result.Min.Lat = math.Min(result.Min.Lat, b.Min.Lat)
+		result.Min.Lon = math.Min(result.Min.Lon, b.Min.Lon)
+		result.Max.Lat = math.Max(result.Max.Lat, b.Max.Lat)
+		result.Max.Lon = math.Max(result.Max.Lon, b.Max.Lon)
+	}
+	return result
+}
+
+// IntersectsCell checks whether the cell intersects any of the polygons
+func (m MultiPolygon) IntersectsCell(h Hash) bool {
+	for _, p := range m {
+		if p.IntersectsCell(h) {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsCell checks whether the cell is fully inside one of the polygons
+func (m MultiPolygon) ContainsCell(h Hash) bool {
+	for _, p := range m {
+		if p.ContainsCell(h) {
+			return true
+		}
+	}
+	return false
+}
+
+// WKT returns the position as POINT(lon lat)
+func (p Pos) WKT() string {
+	var sb strings.Builder
+	sb.WriteString("POINT(")
+	writeWKTCoordinate(&sb, p)
+	sb.WriteString(")")
+	return sb.String()
+}
+
+// WKT returns the rectangle as a counterclockwise POLYGON
+func (r Rectangle) WKT() string {
+	var sb strings.Builder
+	sb.WriteString("POLYGON((")
+	for i, p := range rectangleCorners(r) {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		writeWKTCoordinate(&sb, p)
+	}
+	sb.WriteString("))")
+	return sb.String()
+}
+
+// WKT returns the Rec() of the geohash as a POLYGON
+func (h Hash) WKT() string {
+	return h.Rec().WKT()
+}
+
+// WKB returns the position as a little endian WKB point
+func (p Pos) WKB() []byte {
+	data := make([]byte, 0, wkbHeaderSize+wkbCoordinateSize)
+	data = appendWKBHeader(data, wkbPoint)
+	return appendWKBCoordinate(data, p)
+}
+
+// WKB returns the rectangle as a little endian WKB polygon
+func (r Rectangle) WKB() []byte {
+	corners := rectangleCorners(r)
+
+	data := make([]byte, 0, wkbHeaderSize+4+4+wkbCoordinateSize*len(corners))
+	data = appendWKBHeader(data, wkbPolygon)
+	data = appendUint32(data, 1)
+	data = appendUint32(data, uint32(len(corners)))
+	for _, p := range corners {
+		data = appendWKBCoordinate(data, p)
+	}
+	return data
+}
+
+// WKB returns the Rec() of the geohash as a WKB polygon
+func (h Hash) WKB() []byte {
+	return h.Rec().WKB()
+}
+
+// ParsePointWKT parses POINT(lon lat), keywords are case-insensitive
+func ParsePointWKT(s string) (Pos, error) {
+	p := &wktParser{s: s}
+	if err := p.keyword("POINT"); err != nil {
+		return Pos{}, err
+	}
+	if err := p.expect('('); err != nil {
+		return Pos{}, err
+	}
+	pos, err := p.coordinate()
+	if err != nil {
+		return Pos{}, err
+	}
+	if err := p.expect(')'); err != nil {
+		return Pos{}, err
+	}
+	if err := p.end(); err != nil {
+		return Pos{}, err
+	}
+	return pos, nil
+}
+
+// ParsePointWKB parses a WKB point of either byte order. The EWKB of PostGIS is also accepted,
+// its SRID is skipped without being checked. Points with Z or M coordinates are not supported
+func ParsePointWKB(data []byte) (Pos, error) {
+	if len(data) < wkbHeaderSize {
+		return Pos{}, ErrInvalidWKB
+	}
+
+	var order binary.ByteOrder
+	switch data[0] {
+	case wkbBigEndian:
+		order = binary.BigEndian
+	case wkbLittleEndian:
+		order = binary.LittleEndian
+	default:
+		return Pos{}, ErrInvalidWKB
+	}
+
+	geometryType := order.Uint32(data[1:wkbHeaderSize])
+	data = data[wkbHeaderSize:]
+	if geometryType&wkbSRIDFlag != 0 {
+		if len(data) < wkbSRIDSize {
+			return Pos{}, ErrInvalidWKB
+		}
+		geometryType &^= wkbSRIDFlag
+		data = data[wkbSRIDSize:]
+	}
+
+	if geometryType != wkbPoint || len(data) != wkbCoordinateSize {
+		return Pos{}, ErrInvalidWKB
+	}
+
+	pos := Pos{
+		Lon: math.Float64frombits(order.Uint64(data[:8])),
+		Lat: math.Float64frombits(order.Uint64(data[8:])),
+	}
+	if err := validatePos(pos); err != nil {
+		return Pos{}, err
+	}
+	return pos, nil
+}
+
+// ParsePolygonWKT parses a POLYGON or a MULTIPOLYGON, keywords are case-insensitive.
+// The first ring of a polygon is its exterior, the others are its holes.
+// Each ring must be closed and have at least 4 positions
+func ParsePolygonWKT(s string) (MultiPolygon, error) {
+	p := &wktParser{s: s}
+
+	name := strings.ToUpper(p.word())
+	switch name {
+	case "POLYGON":
+		polygon, err := p.polygon()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.end(); err != nil {
+			return nil, err
+		}
+		return MultiPolygon{polygon}, nil
+
+	case "MULTIPOLYGON":
+		if err := p.expect('('); err != nil {
+			return nil, err
+		}
+
+		var result MultiPolygon
+		for {
+			polygon, err := p.polygon()
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, polygon)
+
+			if !p.accept(',') {
+				break
+			}
+		}
+
+		if err := p.expect(')'); err != nil {
+			return nil, err
+		}
+		if err := p.end(); err != nil {
+			return nil, err
+		}
+		return result, nil
+
+	default:
+		return nil, fmt.Errorf("%w: expected POLYGON or MULTIPOLYGON", ErrInvalidWKT)
+	}
+}
+
+type wktParser struct {
+	s     string
+	index int
+}
+
+func (p *wktParser) skipSpaces() {
+	for p.index < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.index]) >= 0 {
+		p.index++
+	}
+}
+
+func (p *wktParser) word() string {
+	p.skipSpaces()
+
+	start := p.index
+	for p.index < len(p.s) {
+		c := p.s[p.index] | 0x20 // lower case
+		if c < 'a' || c > 'z' {
+			break
+		}
+		p.index++
+	}
+	return p.s[start:p.index]
+}
+
+func (p *wktParser) keyword(name string) error {
+	offset := p.index
+	if !strings.EqualFold(p.word(), name) {
+		return fmt.Errorf("%w: expected %s at offset %d", ErrInvalidWKT, name, offset)
+	}
+	return nil
+}
+
+func (p *wktParser) accept(c byte) bool {
+	p.skipSpaces()
+	if p.index < len(p.s) && p.s[p.index] == c {
+		p.index++
+		return true
+	}
+	return false
+}
+
+func (p *wktParser) expect(c byte) error {
+	if !p.accept(c) {
+		return fmt.Errorf("%w: expected %q at offset %d", ErrInvalidWKT, c, p.index)
+	}
+	return nil
+}
+
+func (p *wktParser) end() error {
+	p.skipSpaces()
+	if p.index != len(p.s) {
+		return fmt.Errorf("%w: unexpected %q at offset %d", ErrInvalidWKT, p.s[p.index], p.index)
+	}
+	return nil
+}
+
+func (p *wktParser) number() (float64, error) {
+	p.skipSpaces()
+
+	start := p.index
+	for p.index < len(p.s) && strings.IndexByte("0123456789+-.eE", p.s[p.index]) >= 0 {
+		p.index++
+	}
+
+	value, err := strconv.ParseFloat(p.s[start:p.index], 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid number at offset %d", ErrInvalidWKT, start)
+	}
+	return value, nil
+}
+
+// coordinate parses "lon lat"
+func (p *wktParser) coordinate() (Pos, error) {
+	lon, err := p.number()
+	if err != nil {
+		return Pos{}, err
+	}
+	lat, err := p.number()
+	if err != nil {
+		return Pos{}, err
+	}
+
+	pos := Pos{Lat: lat, Lon: lon}
+	if err := validatePos(pos); err != nil {
+		return Pos{}, err
+	}
+	return pos, nil
+}
+
+// ring parses "(lon lat, lon lat, ...)"
+func (p *wktParser) ring() ([]Pos, error) {
+	if err := p.expect('('); err != nil {
+		return nil, err
+	}
+
+	var result []Pos
+	for {
+		pos, err := p.coordinate()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, pos)
+
+		if !p.accept(',') {
+			break
+		}
+	}
+
+	if err := p.expect(')'); err != nil {
+		return nil, err
+	}
+	if len(result) < 4 || result[0] != result[len(result)-1] {
+		return nil, fmt.Errorf("%w: ring must be closed and have at least 4 positions", ErrInvalidWKT)
+	}
+	return result, nil
+}
+
+// polygon parses "(ring, ring, ...)"
+func (p *wktParser) polygon() (Polygon, error) {
+	if err := p.expect('('); err != nil {
+		return Polygon{}, err
+	}
+
+	var result Polygon
+	for {
+		ring, err := p.ring()
+		if err != nil {
+			return Polygon{}, err
+		}
+
+		if result.Exterior == nil {
+			result.Exterior = ring
+		} else {
+			result.Holes = append(result.Holes, ring)
+		}
+
+		if !p.accept(',') {
+			break
+		}
+	}
+
+	if err := p.expect(')'); err != nil {
+		return Polygon{}, err
+	}
+	return result, nil
+}
+
+func writeWKTCoordinate(sb *strings.Builder, p Pos) {
+	sb.WriteString(strconv.FormatFloat(p.Lon, 'f', -1, 64))
+	sb.WriteString(" ")
+	sb.WriteString(strconv.FormatFloat(p.Lat, 'f', -1, 64))
+}
+
+func appendWKBHeader(data []byte, geometryType uint32) []byte {
+	data = append(data, wkbLittleEndian)
+	return appendUint32(data, geometryType)
+}
+
+func appendUint32(data []byte, value uint32) []byte {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], value)
+	return append(data, buf[:]...)
+}
+
+func appendWKBCoordinate(data []byte, p Pos) []byte {
+	var buf [wkbCoordinateSize]byte
+	binary.LittleEndian.PutUint64(buf[:8], math.Float64bits(p.Lon))
+	binary.LittleEndian.PutUint64(buf[8:], math.Float64bits(p.Lat))
+	return append(data, buf[:]...)
+}
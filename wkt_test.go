@@ -0,0 +1,171 @@
+package geohash
+
+import (
+	"encoding/hex"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPos_WKT(t *testing.T) {
+	assert.Equal(t, "POINT(106.7009 10.7769)", Pos{Lat: 10.7769, Lon: 106.7009}.WKT())
+	assert.Equal(t, "POINT(-180 -90)", Pos{Lat: -90, Lon: -180}.WKT())
+
+	pos, err := ParsePointWKT("POINT(106.7009 10.7769)")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Pos{Lat: 10.7769, Lon: 106.7009}, pos)
+
+	pos, err = ParsePointWKT("  point ( -1.5e1   2 ) ")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Pos{Lat: 2, Lon: -15}, pos)
+
+	for _, s := range []string{
+		"", "POINT", "POINT()", "POINT(1)", "POINT(1 2 3)", "POINT(1 2", "POINT(1 2))",
+		"POINT EMPTY", "LINESTRING(1 2, 3 4)", "POINT(a b)",
+	} {
+		_, err := ParsePointWKT(s)
+		assert.True(t, errors.Is(err, ErrInvalidWKT), s)
+	}
+
+	_, err = ParsePointWKT("POINT(10 91)")
+	assert.Equal(t, ErrInvalidLatitude, err)
+	_, err = ParsePointWKT("POINT(-181 0)")
+	assert.Equal(t, ErrInvalidLongitude, err)
+}
+
+func TestPos_WKB(t *testing.T) {
+	data := Pos{Lat: 2, Lon: 1}.WKB()
+	assert.Equal(t, "0101000000000000000000f03f0000000000000040", hex.EncodeToString(data))
+
+	pos, err := ParsePointWKB(data)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Pos{Lat: 2, Lon: 1}, pos)
+
+	bigEndian, _ := hex.DecodeString("00000000013ff00000000000004000000000000000")
+	pos, err = ParsePointWKB(bigEndian)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Pos{Lat: 2, Lon: 1}, pos)
+
+	_, err = ParsePointWKB(data[:20])
+	assert.Equal(t, ErrInvalidWKB, err)
+
+	invalid := append([]byte(nil), data...)
+	invalid[0] = 2
+	_, err = ParsePointWKB(invalid)
+	assert.Equal(t, ErrInvalidWKB, err)
+
+	invalid = append([]byte(nil), data...)
+	invalid[1] = wkbPolygon
+	_, err = ParsePointWKB(invalid)
+	assert.Equal(t, ErrInvalidWKB, err)
+
+	_, err = ParsePointWKB(Pos{Lat: 1, Lon: 200}.WKB())
+	assert.Equal(t, ErrInvalidLongitude, err)
+
+	for _, s := range []string{"", "01", "0101000020", "0101000020e6100000"} {
+		invalid, _ = hex.DecodeString(s)
+		_, err = ParsePointWKB(invalid)
+		assert.Equal(t, ErrInvalidWKB, err, s)
+	}
+}
+
+func TestParsePointWKB_EWKB(t *testing.T) {
+	// SELECT ST_AsEWKB(ST_SetSRID(ST_MakePoint(1, 2), 4326))
+	data, _ := hex.DecodeString("0101000020e6100000000000000000f03f0000000000000040")
+	pos, err := ParsePointWKB(data)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Pos{Lat: 2, Lon: 1}, pos)
+
+	bigEndian, _ := hex.DecodeString("0020000001000010e63ff00000000000004000000000000000")
+	pos, err = ParsePointWKB(bigEndian)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Pos{Lat: 2, Lon: 1}, pos)
+
+	// with a Z coordinate
+	pointZ, _ := hex.DecodeString("01010000a0e6100000000000000000f03f00000000000000400000000000000840")
+	_, err = ParsePointWKB(pointZ)
+	assert.Equal(t, ErrInvalidWKB, err)
+}
+
+func TestHash_WKT_And_WKB(t *testing.T) {
+	assert.Equal(t, "POLYGON((0 0,45 0,45 45,0 45,0 0))", mustParse("s").WKT())
+	assert.Equal(t, "POLYGON((168.75 84.375,180 84.375,180 90,168.75 90,168.75 84.375))", mustParse("zz").WKT())
+
+	polygons, err := ParsePolygonWKT(mustParse("s").WKT())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, MultiPolygon{{
+		Exterior: rectangleCorners(mustParse("s").Rec()),
+	}}, polygons)
+
+	data := mustParse("s").WKB()
+	assert.Equal(t, "01"+"03000000"+"01000000"+"05000000"+
+		"0000000000000000"+"0000000000000000"+
+		"0000000000804640"+"0000000000000000"+
+		"0000000000804640"+"0000000000804640"+
+		"0000000000000000"+"0000000000804640"+
+		"0000000000000000"+"0000000000000000", hex.EncodeToString(data))
+}
+
+func TestParsePolygonWKT(t *testing.T) {
+	polygons, err := ParsePolygonWKT("POLYGON((0 0, 10 0, 10 10, 0 10, 0 0), (4 4, 6 4, 6 6, 4 6, 4 4))")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, MultiPolygon{{
+		Exterior: []Pos{{Lat: 0, Lon: 0}, {Lat: 0, Lon: 10}, {Lat: 10, Lon: 10}, {Lat: 10, Lon: 0}, {Lat: 0, Lon: 0}},
+		Holes: [][]Pos{
+			{{Lat: 4, Lon: 4}, {Lat: 4, Lon: 6}, {Lat: 6, Lon: 6}, {Lat: 6, Lon: 4}, {Lat: 4, Lon: 4}},
+		},
+	}}, polygons)
+
+	assert.Equal(t, true, polygons.Contains(Pos{Lat: 1, Lon: 1}))
+	assert.Equal(t, false, polygons.Contains(Pos{Lat: 5, Lon: 5}))
+
+	polygons, err = ParsePolygonWKT("MultiPolygon (((0 0, 1 0, 1 1, 0 0)), ((20 20, 21 20, 21 21, 20 21, 20 20)))")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(polygons))
+	assert.Equal(t, Bounds{
+		Min: Pos{Lat: 0, Lon: 0},
+		Max: Pos{Lat: 21, Lon: 21},
+	}, polygons.RectBound())
+	assert.Equal(t, true, polygons.Contains(Pos{Lat: 20.5, Lon: 20.5}))
+	assert.Equal(t, false, polygons.Contains(Pos{Lat: 10, Lon: 10}))
+
+	for _, s := range []string{
+		"",
+		"POINT(1 2)",
+		"POLYGON",
+		"POLYGON(())",
+		"POLYGON((0 0, 1 0, 1 1))",
+		"POLYGON((0 0, 1 0, 1 1, 0 1))",
+		"POLYGON((0 0, 1 0, 1 1, 0 0)",
+		"POLYGON((0 0, 1 0, 1 1, 0 0)) x",
+		"MULTIPOLYGON((0 0, 1 0, 1 1, 0 0))",
+		"MULTIPOLYGON(((0 0, 1 0, 1 1, 0 0)),)",
+	} {
+		_, err := ParsePolygonWKT(s)
+		assert.True(t, errors.Is(err, ErrInvalidWKT), s)
+	}
+
+	_, err = ParsePolygonWKT("POLYGON((0 0, 1 0, 1 95, 0 0))")
+	assert.Equal(t, ErrInvalidLatitude, err)
+}
+
+func TestMultiPolygon_CoverRegion(t *testing.T) {
+	polygons, err := ParsePolygonWKT("MULTIPOLYGON(((0.1 0.1, 4.1 0.1, 4.1 4.1, 0.1 4.1, 0.1 0.1)), " +
+		"((100.1 10.1, 101 10.1, 101 11, 100.1 11, 100.1 10.1)))")
+	assert.Equal(t, nil, err)
+
+	hashes := CoverRegion(polygons, 3, 3, 0)
+	assert.Equal(t, append(
+		CoverRegion(polygons[0], 3, 3, 0),
+		CoverRegion(polygons[1], 3, 3, 0)...,
+	), hashes)
+
+	hashes = CoverRegion(polygons, 2, 5, 100)
+	assert.LessOrEqual(t, len(hashes), 100)
+	assertValidCovering(t, hashes, 2, 5)
+
+	for _, p := range []Pos{{Lat: 1, Lon: 1}, {Lat: 4, Lon: 4}, {Lat: 10.5, Lon: 100.5}} {
+		assert.True(t, isCoveredBy(p, hashes), p)
+	}
+	assert.False(t, isCoveredBy(Pos{Lat: 50, Lon: 50}, hashes))
+}